@@ -9,10 +9,15 @@ package cli
 
 import (
 	"fmt"
+	"io/fs"
+	"maps"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
-	"github.com/essentialkaos/ek/v13/env"
 	"github.com/essentialkaos/ek/v13/fmtc"
 	"github.com/essentialkaos/ek/v13/fsutil"
 	"github.com/essentialkaos/ek/v13/options"
@@ -29,8 +34,10 @@ import (
 	"github.com/essentialkaos/ek/v13/usage/man"
 	"github.com/essentialkaos/ek/v13/usage/update"
 
+	"github.com/essentialkaos/bop/data"
 	"github.com/essentialkaos/bop/extractor"
 	"github.com/essentialkaos/bop/generator"
+	"github.com/essentialkaos/bop/repo"
 	"github.com/essentialkaos/bop/rpm"
 )
 
@@ -49,6 +56,11 @@ const (
 const (
 	OPT_OUTPUT   = "o:output"
 	OPT_SERVICE  = "s:service"
+	OPT_SVC_FILE = "S:services-file"
+	OPT_PORT     = "p:port"
+	OPT_PIN      = "P:pin-versions"
+	OPT_BATCH    = "B:batch"
+	OPT_IMPORTS  = "I:python-imports"
 	OPT_NO_COLOR = "nc:no-color"
 	OPT_HELP     = "h:help"
 	OPT_VER      = "v:version"
@@ -63,6 +75,11 @@ const (
 var optMap = options.Map{
 	OPT_OUTPUT:   {},
 	OPT_SERVICE:  {Mergeble: true},
+	OPT_SVC_FILE: {},
+	OPT_PORT:     {Mergeble: true},
+	OPT_PIN:      {Type: options.BOOL},
+	OPT_BATCH:    {Type: options.BOOL},
+	OPT_IMPORTS:  {Type: options.BOOL},
 	OPT_NO_COLOR: {Type: options.BOOL},
 	OPT_HELP:     {Type: options.BOOL},
 	OPT_VER:      {Type: options.BOOL},
@@ -104,15 +121,20 @@ func Run(gitRev string, gomod []byte) {
 			WithDeps(deps.Extract(gomod)).
 			Print()
 		os.Exit(0)
-	case options.GetB(OPT_HELP) || len(args) < 2:
+	case options.GetB(OPT_HELP) || len(args) == 0,
+		!options.GetB(OPT_BATCH) && len(args) < 2:
 		genUsage().Print()
 		os.Exit(0)
+	case options.GetB(OPT_BATCH):
+		processBatch(args.Strings())
+		os.Exit(0)
 	}
 
 	name := args.Get(0).String()
 	files := args.Strings()[1:]
 
-	checkSystem()
+	files = resolveFiles(name, files)
+
 	checkFiles(files)
 	processFiles(name, files)
 }
@@ -140,13 +162,6 @@ func configureUI() {
 	}
 }
 
-// checkSystem checks system
-func checkSystem() {
-	if env.Which("rpm") == "" {
-		printErrorAndExit("rpm utility is mandatory for this application")
-	}
-}
-
 // processFiles runs files processing
 func processFiles(name string, files []string) {
 	fmtc.Printf(
@@ -156,44 +171,341 @@ func processFiles(name string, files []string) {
 
 	start := time.Now()
 
-	info, err := extractor.ProcessPackages(files)
+	infos, err := extractor.ProcessPackagesByDist(files)
 
 	if err != nil {
 		printErrorAndExit(err.Error())
 	}
 
-	services := strutil.Fields(options.GetS(OPT_SERVICE))
-	output, data := generator.Generate(name, services, info)
+	if len(infos) == 0 {
+		printErrorAndExit("There are no binary packages among given files")
+	}
+
+	var outputDir, outputFile string
 
 	if options.Has(OPT_OUTPUT) {
-		output = options.GetS(OPT_OUTPUT)
+		switch {
+		case fsutil.IsDir(options.GetS(OPT_OUTPUT)):
+			outputDir = options.GetS(OPT_OUTPUT)
+		case len(infos) > 1:
+			printErrorAndExit("Output must be a directory if packages for different versions of OS are given")
+		default:
+			outputFile = options.GetS(OPT_OUTPUT)
+		}
 	}
 
-	err = os.WriteFile(output, []byte(data), 0644)
+	for _, info := range infos {
+		for _, warn := range info.Warnings {
+			printWarn(warn)
+		}
+	}
+
+	outputs, err := saveRecipes(name, infos, outputDir, outputFile)
 
 	if err != nil {
 		printErrorAndExit(err.Error())
 	}
 
 	fmtc.Printf(
-		"{*}Recipe saved as {#85}%s{!} {s-}(processing took %s){!}\n",
-		output, timeutil.PrettyDuration(time.Since(start)),
+		"{*}%s saved as {#85}%s{!} {s-}(processing took %s){!}\n",
+		pluralize.Pluralize(len(outputs), "Recipe", "Recipes"),
+		strings.Join(outputs, ", "), timeutil.PrettyDuration(time.Since(start)),
 	)
 }
 
+// processBatch generates recipes for every source package found in given
+// packages, directories and repositories
+func processBatch(paths []string) {
+	outputDir := "."
+
+	if options.Has(OPT_OUTPUT) {
+		outputDir = options.GetS(OPT_OUTPUT)
+
+		err := fsutil.ValidatePerms("DWX", outputDir)
+
+		if err != nil {
+			printErrorAndExit(err.Error())
+		}
+	}
+
+	files := collectBatchFiles(paths)
+
+	checkFiles(files)
+
+	groups, err := extractor.GroupBySource(files)
+
+	if err != nil {
+		printErrorAndExit(err.Error())
+	}
+
+	if len(groups) == 0 {
+		printErrorAndExit("There are no binary packages in given paths")
+	}
+
+	fmtc.Printf(
+		"Generating {#85}bibop{!} tests for %s…\n",
+		pluralize.P("%d %s", len(groups), "source package", "source packages"),
+	)
+
+	start := time.Now()
+
+	var hasErrors bool
+
+	for _, name := range slices.Sorted(maps.Keys(groups)) {
+		infos, err := extractor.ProcessPackagesByDist(groups[name])
+
+		if err != nil {
+			printError("%s: %v", name, err)
+			hasErrors = true
+			continue
+		}
+
+		for _, info := range infos {
+			for _, warn := range info.Warnings {
+				printWarn("%s: %s", name, warn)
+			}
+		}
+
+		outputs, err := saveRecipes(name, infos, outputDir, "")
+
+		if err != nil {
+			printError("%s: %v", name, err)
+			hasErrors = true
+			continue
+		}
+
+		fmtc.Printf(
+			"  {s}•{!} {*}%s{!} {s-}(%s){!} → {#85}%s{!}\n", name,
+			pluralize.P("%d %s", len(groups[name]), "package", "packages"),
+			strings.Join(outputs, ", "),
+		)
+	}
+
+	fmtc.Printf(
+		"{*}Recipes saved to {#85}%s{!} {s-}(processing took %s){!}\n",
+		outputDir, timeutil.PrettyDuration(time.Since(start)),
+	)
+
+	if hasErrors {
+		os.Exit(1)
+	}
+}
+
+// saveRecipes generates recipes for every OS version and saves them into given
+// directory or file
+func saveRecipes(name string, infos []*data.Info, outputDir, outputFile string) ([]string, error) {
+	var result []string
+
+	genOptions := getGeneratorOptions()
+	genOptions.DistSuffix = len(infos) > 1
+
+	for _, info := range infos {
+		output, recipe := generator.Generate(name, info, genOptions)
+
+		switch {
+		case outputFile != "":
+			output = outputFile
+		case outputDir != "":
+			output = filepath.Join(outputDir, output)
+		}
+
+		err := os.WriteFile(output, []byte(recipe), 0644)
+
+		if err != nil {
+			return result, err
+		}
+
+		result = append(result, output)
+	}
+
+	return result, nil
+}
+
+// collectBatchFiles collects packages from given files, directories and
+// repositories
+func collectBatchFiles(paths []string) []string {
+	var result []string
+
+	for _, path := range paths {
+		switch {
+		case repo.IsRepository(path):
+			r, err := repo.Open(path)
+
+			if err != nil {
+				printErrorAndExit(err.Error())
+			}
+
+			for _, name := range r.Sources() {
+				result = append(result, r.FindBySource(name)...)
+			}
+
+			// Packages will be read from repository metadata
+			rpm.RegisterReader(r)
+
+		case fsutil.IsDir(path):
+			files, err := findPackages(path)
+
+			if err != nil {
+				printErrorAndExit(err.Error())
+			}
+
+			result = append(result, files...)
+
+		default:
+			result = append(result, path)
+		}
+	}
+
+	return result
+}
+
+// findPackages recursively finds all rpm packages in given directory
+func findPackages(dir string) ([]string, error) {
+	var result []string
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() && strings.HasSuffix(d.Name(), ".rpm") && !strings.HasSuffix(d.Name(), ".src.rpm") {
+			result = append(result, path)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// getGeneratorOptions returns generator options based on command-line options
+func getGeneratorOptions() generator.Options {
+	services := strutil.Fields(options.GetS(OPT_SERVICE))
+
+	if options.Has(OPT_SVC_FILE) {
+		fileServices, err := readServicesFile(options.GetS(OPT_SVC_FILE))
+
+		if err != nil {
+			printErrorAndExit(err.Error())
+		}
+
+		services = append(services, fileServices...)
+	}
+
+	ports, err := parsePortHints(strutil.Fields(options.GetS(OPT_PORT)))
+
+	if err != nil {
+		printErrorAndExit(err.Error())
+	}
+
+	return generator.Options{
+		Services:      services,
+		PinVersions:   options.GetB(OPT_PIN),
+		PythonImports: options.GetB(OPT_IMPORTS),
+		Ports:         ports,
+	}
+}
+
+// parsePortHints parses ports used by services (e.g. redis:6379, nginx:80/http,
+// app:8080/http:401)
+func parsePortHints(hints []string) (data.PortMap, error) {
+	result := make(data.PortMap)
+
+	for _, hint := range hints {
+		service, address, _ := strings.Cut(hint, ":")
+		address, proto, _ := strings.Cut(address, "/")
+		proto, status, _ := strings.Cut(proto, ":")
+		port := data.ParsePort(address)
+
+		if service == "" || port == nil || (proto != "" && proto != "tcp" && proto != "http") ||
+			(proto != "http" && status != "") {
+			return nil, fmt.Errorf("Invalid port definition \"%s\"", hint)
+		}
+
+		if proto == "http" {
+			port.HTTPStatus = 200
+
+			if status != "" {
+				port.HTTPStatus, _ = strconv.Atoi(status)
+			}
+
+			if port.HTTPStatus < 100 || port.HTTPStatus > 599 {
+				return nil, fmt.Errorf("Invalid HTTP status in port definition \"%s\"", hint)
+			}
+		}
+
+		result.Add(service, port)
+	}
+
+	return result, nil
+}
+
+// readServicesFile reads list of services and instances of templated services
+// from file
+func readServicesFile(file string) ([]string, error) {
+	data, err := os.ReadFile(file)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't read services file: %w", err)
+	}
+
+	var result []string
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		result = append(result, strutil.Fields(line)...)
+	}
+
+	return result, nil
+}
+
+// resolveFiles replaces paths to local repositories with paths to packages built
+// from source package with given name
+func resolveFiles(name string, files []string) []string {
+	var result []string
+
+	for _, file := range files {
+		if !repo.IsRepository(file) {
+			result = append(result, file)
+			continue
+		}
+
+		r, err := repo.Open(file)
+
+		if err != nil {
+			printErrorAndExit(err.Error())
+		}
+
+		pkgs := r.FindBySource(name)
+
+		if len(pkgs) == 0 {
+			printErrorAndExit("Repository %s doesn't contain packages built from %s source package", file, name)
+		}
+
+		// Packages will be read from repository metadata
+		rpm.RegisterReader(r)
+
+		result = append(result, pkgs...)
+	}
+
+	return result
+}
+
 // checkFiles checks input files
 func checkFiles(files []string) {
 	var hasErrors bool
 
 	for _, file := range files {
 		switch {
+		case rpm.IsPackage(file):
+			continue
 		case !fsutil.IsExist(file):
 			printError("%s does not exist", file)
 			hasErrors = true
 		case !fsutil.IsReadable(file):
 			printError("%s is not readable", file)
 			hasErrors = true
-		case !rpm.IsPackage(file):
+		default:
 			printError("%s is not an rpm package", file)
 			hasErrors = true
 		}
@@ -209,6 +521,11 @@ func printError(f string, a ...interface{}) {
 	fmtc.Fprintf(os.Stderr, "{r}"+f+"{!}\n", a...)
 }
 
+// printWarn prints warning message to console
+func printWarn(f string, a ...interface{}) {
+	fmtc.Fprintf(os.Stderr, "{y}"+f+"{!}\n", a...)
+}
+
 // printErrorAndExit print error message and exit with exit code 1
 func printErrorAndExit(f string, a ...interface{}) {
 	printError(f, a...)
@@ -247,19 +564,29 @@ func printMan() {
 
 // genUsage generates usage info
 func genUsage() *usage.Info {
-	info := usage.NewInfo("", "name", "package…")
+	info := usage.NewInfo("", "name", "package|repository…")
 
 	info.AppNameColorTag = colorTagApp
 
-	info.AddOption(OPT_OUTPUT, "Output file", "file")
-	info.AddOption(OPT_SERVICE, "List of services for checking {c}(mergeable){!}", "service")
+	info.AddOption(OPT_OUTPUT, "Output file {s-}(output directory in batch mode){!}", "file")
+	info.AddOption(OPT_SERVICE, "List of services or service instances for checking {c}(mergeable){!}", "service")
+	info.AddOption(OPT_SVC_FILE, "File with list of services or service instances for checking", "file")
+	info.AddOption(OPT_PORT, "Port used by service {s-}(service:port[/tcp|/http[:status]]){!} {c}(mergeable){!}", "port")
+	info.AddOption(OPT_PIN, "Use exact package versions in dependencies")
+	info.AddOption(OPT_IMPORTS, "Generate Python import tests with version check")
+	info.AddOption(OPT_BATCH, "Generate one recipe per source package from given packages, directories and repositories")
 	info.AddOption(OPT_NO_COLOR, "Disable colors in output")
 	info.AddOption(OPT_HELP, "Show this help message")
 	info.AddOption(OPT_VER, "Show version")
 
 	info.AddExample("htop htop*.rpm", "Generate simple tests for package")
 	info.AddExample("redis redis*.rpm -s redis", "Generate tests with service check")
+	info.AddExample("redis redis*.rpm -s redis -p redis:6379", "Generate tests with service and port check")
+	info.AddExample("openvpn openvpn*.rpm -s openvpn-server@main", "Generate tests with check for instance of templated service")
+	info.AddExample("-I requests python3-requests*.rpm", "Generate tests with Python modules import check")
 	info.AddExample("-o zl.recipe zlib zlib*.rpm minizip*.rpm", "Generate tests with custom name")
+	info.AddExample("nginx /path/to/repo", "Generate tests for packages built from nginx source package in local repository")
+	info.AddExample("-B -o recipes /path/to/build/output", "Generate tests for every source package in directory")
 
 	return info
 }
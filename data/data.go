@@ -8,7 +8,14 @@ package data
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/essentialkaos/bop/distro"
 	"github.com/essentialkaos/bop/rpm"
+	"github.com/essentialkaos/bop/systemd"
 )
 
 // ////////////////////////////////////////////////////////////////////////////////// //
@@ -16,17 +23,34 @@ import (
 // Info contains info about all packages
 type Info struct {
 	Dist        string
+	Distro      *distro.Distro
 	Pkgs        []string
+	Packages    []*rpm.Package
 	Apps        []string
 	Configs     []*rpm.Object
 	SharedLibs  []string
 	StaticLibs  []*rpm.Object
 	Headers     []string
+	HeaderFiles []string
 	PkgConfigs  []string
+	PCModules   map[string]*PkgConfig
 	Completions []string
 	Users       UserMap
 	Groups      GroupMap
 	Services    []string
+	Sockets     []string
+	Timers      []string
+	PathUnits   []string
+	Targets     []string
+	Units       map[string]*systemd.Unit
+	Ports       PortMap
+	RuntimeDirs []*rpm.Object
+	ELFs        []*ELF
+
+	VirtualProvides []string
+	UnsatisfiedDeps []string
+	Alternatives    []*Alternative
+	Warnings        []string
 
 	Python2Dirs    []*rpm.Object
 	Python2Files   []*rpm.Object
@@ -34,7 +58,20 @@ type Info struct {
 	Python3Dirs    []*rpm.Object
 	Python3Files   []*rpm.Object
 	Python3Modules []string
+	Python3Exts    []string
 	PythonWheels   []*rpm.Object
+	PythonDists    []*PythonDist
+
+	PerlModules   []*Module
+	RubyGems      []*Module
+	NodeModules   []*Module
+	PHPExtensions []*Module
+	LuaModules    []*Module
+
+	KernelModules []*KernelModule
+	UdevRules     []*rpm.Object
+	ModprobeConfs []*rpm.Object
+	DracutModules []*rpm.Object
 }
 
 // UserMap is map user name → user info
@@ -59,4 +96,131 @@ type Group struct {
 	GID  string
 }
 
+// PkgConfig contains info from pkg-config file
+type PkgConfig struct {
+	Name     string
+	Version  string
+	Requires []*PkgConfigRequire
+	Libs     []string
+}
+
+// PkgConfigRequire contains info about module required by pkg-config file
+type PkgConfigRequire struct {
+	Name    string
+	Op      string
+	Version string
+}
+
+// PythonDist contains info about Python distribution (dist-info or egg-info)
+type PythonDist struct {
+	Name       string
+	Version    string
+	PkgVersion string // Package version in Python format
+	Path       string
+	Metadata   string
+	Scripts    []string
+}
+
+// Module contains info about Perl, Ruby, Node.js, PHP or Lua module
+type Module struct {
+	Name     string
+	Version  string
+	Path     string
+	Runtime  string // Interpreter used for loading module
+	Loadable bool   // Module can be loaded (false for Node.js packages without entry point)
+}
+
+// KernelModule contains info about kernel module
+type KernelModule struct {
+	Name          string
+	KernelVersion string
+	File          *rpm.Object
+}
+
+// Alternative contains info about link managed by alternatives system
+type Alternative struct {
+	Name     string
+	Link     string
+	Path     string
+	Priority string
+	Slaves   []*Alternative
+}
+
+// ELF contains info about ELF binary or shared library
+type ELF struct {
+	Path    string
+	SONAME  string
+	Needed  []string
+	RPath   []string
+	BadPath []string // Run-time search paths pointing to system or unknown directories
+	Symbol  string   // Exported function of shared library
+	IsLib   bool
+}
+
 // ////////////////////////////////////////////////////////////////////////////////// //
+
+// PortMap is map service name → ports
+type PortMap map[string][]*Port
+
+// Port contains info about port used by service
+type Port struct {
+	Host       string
+	Port       int
+	HTTPStatus int // Expected HTTP status code, 0 if port is checked only by connection
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ParsePort parses listen address (e.g. "80", "127.0.0.1:6379", "[::]:443")
+func ParsePort(address string) *Port {
+	if strings.HasPrefix(address, "/") || strings.HasPrefix(address, "@") {
+		return nil
+	}
+
+	host, portStr, err := net.SplitHostPort(address)
+
+	if err != nil {
+		host, portStr = "", address
+	}
+
+	port, err := strconv.Atoi(portStr)
+
+	if err != nil || port <= 0 || port > 65535 {
+		return nil
+	}
+
+	switch host {
+	case "0.0.0.0", "::", "*", "":
+		host = ""
+	default:
+		if net.ParseIP(host) == nil && host != "localhost" {
+			return nil
+		}
+	}
+
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+
+	return &Port{Host: host, Port: port}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Add adds port to service. If port is already added, it will be replaced by
+// the given one, so ports added later (e.g. from user hints) take precedence.
+func (m PortMap) Add(service string, port *Port) {
+	for i, p := range m[service] {
+		if p.Host == port.Host && p.Port == port.Port {
+			m[service][i] = port
+			return
+		}
+	}
+
+	m[service] = append(m[service], port)
+}
+
+// Address returns port address
+func (p *Port) Address() string {
+	return fmt.Sprintf("%s:%d", p.Host, p.Port)
+}
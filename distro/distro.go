@@ -0,0 +1,244 @@
+package distro
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Distribution families
+const (
+	FAMILY_UNKNOWN = ""
+	FAMILY_EL      = "el"
+	FAMILY_FEDORA  = "fedora"
+	FAMILY_AMAZON  = "amazon"
+	FAMILY_SUSE    = "suse"
+)
+
+// Init systems
+const (
+	INIT_SYSV    = "sysv"
+	INIT_SYSTEMD = "systemd"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Distro contains info about distribution
+type Distro struct {
+	Dist    string // Dist tag (e.g. el9, fc40, amzn2023)
+	Family  string // Distribution family
+	Version int    // Major version
+	Init    string // Init system
+	Python2 bool   // Python 2 interpreter can be installed from distribution repositories
+	Python3 bool   // Python 3 interpreter can be installed from distribution repositories
+
+	// Default Python 3 is older than 3.8 and doesn't have importlib.metadata
+	LegacyPython3 bool
+
+	// Version of default Python 3 interpreter (e.g. 3.9)
+	Python3Version string
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// distRegex is regex for parsing dist tag
+var distRegex = regexp.MustCompile(`^(el|fc|amzn|lp|sle)([0-9]+)`)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Parse parses dist tag and returns info about distribution
+func Parse(dist string) *Distro {
+	d := &Distro{Dist: dist, Init: INIT_SYSTEMD, Python3: true}
+	match := distRegex.FindStringSubmatch(dist)
+
+	if match == nil {
+		return d
+	}
+
+	d.Version, _ = strconv.Atoi(match[2])
+
+	switch match[1] {
+	case "el":
+		d.Family = FAMILY_EL
+		d.Python2 = d.Version <= 8 // EL 8 provides python2 in AppStream
+		d.Python3 = d.Version >= 7
+		d.LegacyPython3 = d.Version <= 8
+		d.Python3Version = getELPython3Version(d.Version)
+
+		if d.Version <= 6 {
+			d.Init = INIT_SYSV
+		}
+
+	case "fc":
+		d.Family = FAMILY_FEDORA
+		d.Python2 = true // Fedora still provides python2.7 for legacy software
+		d.LegacyPython3 = d.Version < 32
+		d.Python3Version = getFedoraPython3Version(d.Version)
+
+		if d.Version < 15 {
+			d.Init = INIT_SYSV
+		}
+
+	case "amzn":
+		d.Family = FAMILY_AMAZON
+		d.Python2 = d.Version < 2023
+		d.Python3 = d.Version >= 2
+		d.LegacyPython3 = d.Version < 2023
+		d.Python3Version = getAmazonPython3Version(d.Version)
+
+		if d.Version < 2 {
+			d.Init = INIT_SYSV
+		}
+
+	case "lp":
+		// Leap uses version with service pack (e.g. lp155 → 15.5)
+		d.Family = FAMILY_SUSE
+		d.Version /= 10
+		d.Python2 = d.Version <= 15
+		d.LegacyPython3 = d.Version <= 15
+		d.Python3Version = getSUSEPython3Version(d.Version)
+
+	case "sle":
+		d.Family = FAMILY_SUSE
+		d.Python2 = d.Version <= 15
+		d.LegacyPython3 = d.Version <= 15
+		d.Python3Version = getSUSEPython3Version(d.Version)
+	}
+
+	return d
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// IsKnown returns true if distribution is known
+func (d *Distro) IsKnown() bool {
+	return d != nil && d.Family != FAMILY_UNKNOWN
+}
+
+// IsSystemd returns true if distribution uses systemd as init system
+func (d *Distro) IsSystemd() bool {
+	return d == nil || d.Init == INIT_SYSTEMD
+}
+
+// HasPython2 returns true if Python 2 interpreter is available on distribution
+func (d *Distro) HasPython2() bool {
+	return !d.IsKnown() || d.Python2
+}
+
+// HasPython3 returns true if Python 3 interpreter is available on distribution
+func (d *Distro) HasPython3() bool {
+	return !d.IsKnown() || d.Python3
+}
+
+// Suffix returns suffix for recipe name (e.g. c9, fc40, amzn2023)
+func (d *Distro) Suffix() string {
+	switch {
+	case d == nil || d.Dist == "":
+		return ""
+	case d.Family == FAMILY_EL:
+		return "c" + strconv.Itoa(d.Version)
+	case d.Family == FAMILY_SUSE:
+		return "sle" + strconv.Itoa(d.Version)
+	}
+
+	return d.Dist
+}
+
+// String returns human-readable name of distribution
+func (d *Distro) String() string {
+	if d == nil {
+		return ""
+	}
+
+	switch d.Family {
+	case FAMILY_EL:
+		return fmt.Sprintf("EL %d", d.Version)
+	case FAMILY_FEDORA:
+		return fmt.Sprintf("Fedora %d", d.Version)
+	case FAMILY_AMAZON:
+		return fmt.Sprintf("Amazon Linux %d", d.Version)
+	case FAMILY_SUSE:
+		return fmt.Sprintf("SUSE Linux %d", d.Version)
+	}
+
+	return d.Dist
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// getELPython3Version returns version of default Python 3 on EL
+func getELPython3Version(version int) string {
+	switch {
+	case version >= 10:
+		return "3.12"
+	case version == 9:
+		return "3.9"
+	case version >= 7:
+		return "3.6"
+	}
+
+	return ""
+}
+
+// getFedoraPython3Version returns version of default Python 3 on Fedora
+func getFedoraPython3Version(version int) string {
+	switch {
+	case version >= 43:
+		return "3.14"
+	case version >= 41:
+		return "3.13"
+	case version >= 39:
+		return "3.12"
+	case version >= 37:
+		return "3.11"
+	case version >= 35:
+		return "3.10"
+	case version >= 33:
+		return "3.9"
+	case version == 32:
+		return "3.8"
+	case version >= 29:
+		return "3.7"
+	case version >= 26:
+		return "3.6"
+	case version >= 24:
+		return "3.5"
+	case version == 23:
+		return "3.4"
+	}
+
+	return ""
+}
+
+// getAmazonPython3Version returns version of default Python 3 on Amazon Linux
+func getAmazonPython3Version(version int) string {
+	switch {
+	case version >= 2023:
+		return "3.9"
+	case version == 2:
+		return "3.7"
+	}
+
+	return ""
+}
+
+// getSUSEPython3Version returns version of default Python 3 on SUSE
+func getSUSEPython3Version(version int) string {
+	switch {
+	case version >= 16:
+		return "3.13"
+	case version == 15:
+		return "3.6"
+	}
+
+	return ""
+}
@@ -0,0 +1,92 @@
+package distro
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"testing"
+
+	"github.com/essentialkaos/bop/rpm"
+
+	. "github.com/essentialkaos/check"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func Test(t *testing.T) { TestingT(t) }
+
+type DistroSuite struct{}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var _ = Suite(&DistroSuite{})
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (s *DistroSuite) TestParse(c *C) {
+	tests := []struct {
+		Release string
+		Distro  Distro
+		Suffix  string
+		Name    string
+	}{
+		{"1.el6", Distro{"el6", FAMILY_EL, 6, INIT_SYSV, true, false, true, ""}, "c6", "EL 6"},
+		{"1.el7_9", Distro{"el7_9", FAMILY_EL, 7, INIT_SYSTEMD, true, true, true, "3.6"}, "c7", "EL 7"},
+		{"1.el8", Distro{"el8", FAMILY_EL, 8, INIT_SYSTEMD, true, true, true, "3.6"}, "c8", "EL 8"},
+		{"1.el8_9", Distro{"el8_9", FAMILY_EL, 8, INIT_SYSTEMD, true, true, true, "3.6"}, "c8", "EL 8"},
+		{"3.module+el8.9.0+20000+abcd1234", Distro{"el8", FAMILY_EL, 8, INIT_SYSTEMD, true, true, true, "3.6"}, "c8", "EL 8"},
+		{"1.el9", Distro{"el9", FAMILY_EL, 9, INIT_SYSTEMD, false, true, false, "3.9"}, "c9", "EL 9"},
+		{"2.el10", Distro{"el10", FAMILY_EL, 10, INIT_SYSTEMD, false, true, false, "3.12"}, "c10", "EL 10"},
+		{"1.fc14", Distro{"fc14", FAMILY_FEDORA, 14, INIT_SYSV, true, true, true, ""}, "fc14", "Fedora 14"},
+		{"1.fc31", Distro{"fc31", FAMILY_FEDORA, 31, INIT_SYSTEMD, true, true, true, "3.7"}, "fc31", "Fedora 31"},
+		{"1.fc40", Distro{"fc40", FAMILY_FEDORA, 40, INIT_SYSTEMD, true, true, false, "3.12"}, "fc40", "Fedora 40"},
+		{"1.amzn1", Distro{"amzn1", FAMILY_AMAZON, 1, INIT_SYSV, true, false, true, ""}, "amzn1", "Amazon Linux 1"},
+		{"1.amzn2", Distro{"amzn2", FAMILY_AMAZON, 2, INIT_SYSTEMD, true, true, true, "3.7"}, "amzn2", "Amazon Linux 2"},
+		{"1.amzn2023.0.2", Distro{"amzn2023", FAMILY_AMAZON, 2023, INIT_SYSTEMD, false, true, false, "3.9"}, "amzn2023", "Amazon Linux 2023"},
+		{"lp155.1.1", Distro{"lp155", FAMILY_SUSE, 15, INIT_SYSTEMD, true, true, true, "3.6"}, "sle15", "SUSE Linux 15"},
+		{"150500.3.3", Distro{"sle15", FAMILY_SUSE, 15, INIT_SYSTEMD, true, true, true, "3.6"}, "sle15", "SUSE Linux 15"},
+		{"1.sle16", Distro{"sle16", FAMILY_SUSE, 16, INIT_SYSTEMD, false, true, false, "3.13"}, "sle16", "SUSE Linux 16"},
+		{"1.mga9", Distro{"mga9", FAMILY_UNKNOWN, 0, INIT_SYSTEMD, false, true, false, ""}, "mga9", "mga9"},
+		{"1.1", Distro{"1", FAMILY_UNKNOWN, 0, INIT_SYSTEMD, false, true, false, ""}, "1", "1"},
+		{"2", Distro{"2", FAMILY_UNKNOWN, 0, INIT_SYSTEMD, false, true, false, ""}, "2", "2"},
+	}
+
+	for _, test := range tests {
+		dist := rpm.ExtractDist(test.Release)
+		d := Parse(dist)
+
+		c.Assert(dist, Equals, test.Distro.Dist, Commentf("Release: %s", test.Release))
+		c.Assert(*d, DeepEquals, test.Distro, Commentf("Release: %s", test.Release))
+		c.Assert(d.Suffix(), Equals, test.Suffix, Commentf("Release: %s", test.Release))
+		c.Assert(d.String(), Equals, test.Name, Commentf("Release: %s", test.Release))
+		c.Assert(d.IsKnown(), Equals, test.Distro.Family != FAMILY_UNKNOWN, Commentf("Release: %s", test.Release))
+		c.Assert(d.IsSystemd(), Equals, test.Distro.Init == INIT_SYSTEMD, Commentf("Release: %s", test.Release))
+	}
+}
+
+func (s *DistroSuite) TestPythonAvailability(c *C) {
+	c.Assert(Parse("el6").HasPython2(), Equals, true)
+	c.Assert(Parse("el6").HasPython3(), Equals, false)
+	c.Assert(Parse("el8").HasPython2(), Equals, true)
+	c.Assert(Parse("el9").HasPython2(), Equals, false)
+	c.Assert(Parse("fc40").HasPython2(), Equals, true)
+	c.Assert(Parse("amzn2023").HasPython2(), Equals, false)
+
+	// Availability of interpreters on unknown distributions can't be checked
+	c.Assert(Parse("mga9").HasPython2(), Equals, true)
+	c.Assert(Parse("mga9").HasPython3(), Equals, true)
+}
+
+func (s *DistroSuite) TestNil(c *C) {
+	var d *Distro
+
+	c.Assert(d.IsKnown(), Equals, false)
+	c.Assert(d.IsSystemd(), Equals, true)
+	c.Assert(d.Suffix(), Equals, "")
+	c.Assert(d.String(), Equals, "")
+	c.Assert(Parse("").Suffix(), Equals, "")
+}
@@ -0,0 +1,117 @@
+package extractor
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"path"
+	"slices"
+
+	"github.com/essentialkaos/bop/data"
+	"github.com/essentialkaos/bop/rpm"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// alternativesOptions contains alternatives options with values
+var alternativesOptions = map[string]bool{
+	"--initscript": true,
+	"--family":     true,
+	"--altdir":     true,
+	"--admindir":   true,
+	"--log":        true,
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// addAlternativesInfo extracts info about alternatives registered by package
+// scriptlets
+func addAlternativesInfo(info *data.Info, pkg *rpm.Package) {
+	for _, scriptlet := range pkg.Scriptlets {
+		if isParsableScriptlet(scriptlet) {
+			extractAlternativesData(scriptlet.Body, info)
+		}
+	}
+}
+
+// extractAlternativesData extracts info about alternatives registered by
+// scriptlet
+func extractAlternativesData(script string, info *data.Info) {
+	for _, cmd := range splitShellCommands(script) {
+		switch path.Base(cmd[0]) {
+		case "alternatives", "update-alternatives":
+			alt := parseAlternativesCommand(cmd[1:])
+
+			if alt == nil || slices.ContainsFunc(info.Alternatives, func(a *data.Alternative) bool {
+				return a.Name == alt.Name && a.Path == alt.Path
+			}) {
+				continue
+			}
+
+			info.Alternatives = append(info.Alternatives, alt)
+		}
+	}
+}
+
+// parseAlternativesCommand parses arguments of alternatives command with
+// --install action
+func parseAlternativesCommand(args []string) *data.Alternative {
+	var result *data.Alternative
+
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "--install" && i+4 < len(args):
+			if !isLiteralArgs(args[i+1 : i+4]...) {
+				return nil
+			}
+
+			result = &data.Alternative{
+				Link:     args[i+1],
+				Name:     args[i+2],
+				Path:     args[i+3],
+				Priority: getLiteral(args[i+4]),
+			}
+
+			i += 4
+
+		case (arg == "--slave" || arg == "--follower") && i+3 < len(args):
+			if result != nil && isLiteralArgs(args[i+1:i+4]...) {
+				result.Slaves = append(result.Slaves, &data.Alternative{
+					Link: args[i+1],
+					Name: args[i+2],
+					Path: args[i+3],
+				})
+			}
+
+			i += 3
+
+		case alternativesOptions[arg]:
+			i++
+
+		case arg == "--verbose", arg == "--quiet", arg == "--keep-foreign":
+			continue
+
+		default:
+			// Other actions (--set, --remove, --config…) don't register
+			// alternatives
+			return nil
+		}
+	}
+
+	return result
+}
+
+// isLiteralArgs returns true if all arguments don't contain variables
+func isLiteralArgs(args ...string) bool {
+	for _, arg := range args {
+		if !isLiteral(arg) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,208 @@
+package extractor
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bytes"
+	"debug/elf"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/essentialkaos/bop/data"
+	"github.com/essentialkaos/bop/rpm"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// MAX_ELF_SIZE is maximum size of ELF file which will be analyzed
+const MAX_ELF_SIZE = 128 * 1024 * 1024
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var binDirs = []string{"/usr/bin/", "/usr/sbin/", "/bin/", "/sbin/"}
+
+var systemLibDirs = []string{"/lib", "/lib64", "/usr/lib", "/usr/lib64"}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// addELFInfo extracts info about SONAME, required libraries and run-time search
+// paths from binaries and shared libraries
+func addELFInfo(info *data.Info, pkg *rpm.Package, payload *payloadReader) {
+	var files []string
+
+	libs := make(map[string]bool)
+
+	for _, obj := range pkg.Payload {
+		if obj.IsDir || obj.IsLink || obj.Size > MAX_ELF_SIZE {
+			continue
+		}
+
+		switch {
+		case matchAnyGlob(obj.Path, sharedLibsGlobs):
+			libs[obj.Path] = true
+			files = append(files, obj.Path)
+		case obj.Mode&0111 != 0 && isBinDirObject(obj.Path):
+			files = append(files, obj.Path)
+		}
+	}
+
+	var objs []*data.ELF
+
+	// Binaries are parsed while payload is read, so only one of them is kept
+	// in memory at a time
+	payload.Read(
+		files,
+		func(file string, content []byte) {
+			obj, err := parseELF(content)
+
+			if err == nil && obj != nil {
+				obj.Path, obj.IsLib = file, libs[file]
+				objs = append(objs, obj)
+			}
+		},
+		func(bool) {
+			slices.SortFunc(objs, func(a, b *data.ELF) int {
+				return strings.Compare(a.Path, b.Path)
+			})
+
+			info.ELFs = append(info.ELFs, objs...)
+		},
+	)
+}
+
+// checkELFRPaths moves unexpected run-time search paths to the separate list
+// and adds warnings about them
+func checkELFRPaths(info *data.Info) {
+	dirs := make(map[string]bool)
+
+	for _, pkg := range info.Packages {
+		for _, obj := range pkg.Payload {
+			if obj.IsDir {
+				dirs[obj.Path] = true
+			} else {
+				dirs[path.Dir(obj.Path)] = true
+			}
+		}
+	}
+
+	for _, obj := range info.ELFs {
+		var rpaths []string
+
+		for _, rpath := range obj.RPath {
+			dir := path.Clean(rpath)
+
+			switch {
+			case strings.HasPrefix(rpath, "$ORIGIN"),
+				strings.HasPrefix(rpath, "${ORIGIN}"):
+				rpaths = append(rpaths, rpath)
+			case slices.Contains(systemLibDirs, dir):
+				obj.BadPath = append(obj.BadPath, rpath)
+				info.Warnings = append(info.Warnings, fmt.Sprintf(
+					"%s has RPATH/RUNPATH pointing to system directory %s", obj.Path, rpath,
+				))
+			case !dirs[dir]:
+				obj.BadPath = append(obj.BadPath, rpath)
+				info.Warnings = append(info.Warnings, fmt.Sprintf(
+					"%s has RPATH/RUNPATH pointing to directory %s which is not a part of packages", obj.Path, rpath,
+				))
+			default:
+				rpaths = append(rpaths, rpath)
+			}
+		}
+
+		obj.RPath = rpaths
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// parseELF parses ELF file and returns info about dynamic section. Non-ELF
+// files (e.g. scripts) are ignored.
+func parseELF(content []byte) (*data.ELF, error) {
+	if !bytes.HasPrefix(content, []byte(elf.ELFMAG)) {
+		return nil, nil
+	}
+
+	file, err := elf.NewFile(bytes.NewReader(content))
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	if file.Section(".dynamic") == nil {
+		return &data.ELF{}, nil
+	}
+
+	result := &data.ELF{}
+
+	soname, _ := file.DynString(elf.DT_SONAME)
+
+	if len(soname) != 0 {
+		result.SONAME = soname[0]
+		result.Symbol = findExportedFunction(file)
+	}
+
+	result.Needed, _ = file.DynString(elf.DT_NEEDED)
+
+	// DT_RUNPATH takes precedence over DT_RPATH
+	rpath, _ := file.DynString(elf.DT_RUNPATH)
+
+	if len(rpath) == 0 {
+		rpath, _ = file.DynString(elf.DT_RPATH)
+	}
+
+	for _, value := range rpath {
+		for _, dir := range strings.Split(value, ":") {
+			if dir != "" && !slices.Contains(result.RPath, dir) {
+				result.RPath = append(result.RPath, dir)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// findExportedFunction returns name of the first (in alphabetical order)
+// function exported by shared library
+func findExportedFunction(file *elf.File) string {
+	var result string
+
+	symbols, _ := file.DynamicSymbols()
+
+	for _, sym := range symbols {
+		switch {
+		case elf.ST_TYPE(sym.Info) != elf.STT_FUNC,
+			elf.ST_BIND(sym.Info) != elf.STB_GLOBAL,
+			elf.ST_VISIBILITY(sym.Other) != elf.STV_DEFAULT,
+			sym.Section == elf.SHN_UNDEF,
+			strings.HasPrefix(sym.Name, "_"):
+			continue
+		}
+
+		if result == "" || sym.Name < result {
+			result = sym.Name
+		}
+	}
+
+	return result
+}
+
+// isBinDirObject returns true if object is placed in directory with binaries
+func isBinDirObject(file string) bool {
+	for _, dir := range binDirs {
+		if strings.HasPrefix(file, dir) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -8,8 +8,8 @@ package extractor
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 import (
-	"bufio"
 	"fmt"
+	"maps"
 	"path"
 	"path/filepath"
 	"slices"
@@ -20,16 +20,13 @@ import (
 	"github.com/essentialkaos/ek/v13/strutil"
 
 	"github.com/essentialkaos/bop/data"
+	"github.com/essentialkaos/bop/distro"
 	"github.com/essentialkaos/bop/rpm"
+	"github.com/essentialkaos/bop/systemd"
 )
 
 // ////////////////////////////////////////////////////////////////////////////////// //
 
-var pkgConfigGlobs = []string{
-	"/usr/lib/pkgconfig/*.pc",
-	"/usr/lib64/pkgconfig/*.pc",
-}
-
 var staticLibsGlobs = []string{
 	"/usr/lib/*.a",
 	"/usr/lib64/*.a",
@@ -45,31 +42,67 @@ var systemdUnitGlobs = []string{
 	"/usr/lib/systemd/user/*.service",
 }
 
+var systemdExtraUnitGlobs = []string{
+	"/usr/lib/systemd/system/*.socket",
+	"/usr/lib/systemd/system/*.timer",
+	"/usr/lib/systemd/system/*.path",
+	"/usr/lib/systemd/system/*.target",
+}
+
 var includeDir = "/usr/include"
 
 // ////////////////////////////////////////////////////////////////////////////////// //
 
-// ProcessPackages reads rpm files and extracts info from them
-func ProcessPackages(files []string) (*data.Info, error) {
+// ProcessPackagesByDist reads rpm files and extracts info from them separately
+// for every OS version
+func ProcessPackagesByDist(files []string) ([]*data.Info, error) {
 	pkgs, err := readPackagesData(files)
+
 	if err != nil {
 		return nil, err
 	}
 
-	if isPackagesWithMixedDist(pkgs) {
-		return nil, fmt.Errorf("Packages for different versions of OS can not be used for test generation")
+	var result []*data.Info
+
+	for _, distPkgs := range splitPackagesByDist(pkgs) {
+		result = append(result, extractPackagesInfo(distPkgs))
 	}
 
-	if err != nil {
-		return nil, err
+	return result, nil
+}
+
+// GroupBySource reads basic info from rpm files and groups them by name of source
+// package
+func GroupBySource(files []string) (map[string][]string, error) {
+	result := make(map[string][]string)
+
+	for _, file := range files {
+		pkg, err := rpm.ReadInfo(file)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if pkg.IsSrc {
+			continue
+		}
+
+		name := pkg.SourceName()
+
+		if name == "" {
+			name = pkg.Name
+		}
+
+		result[name] = append(result[name], file)
 	}
 
-	return extractPackagesInfo(pkgs), nil
+	return result, nil
 }
 
 // ////////////////////////////////////////////////////////////////////////////////// //
 
-// readPackagesData read info packages info from rpm files
+// readPackagesData read info packages info from rpm files using registered
+// package readers
 func readPackagesData(files []string) ([]*rpm.Package, error) {
 	var pkgs []*rpm.Package
 
@@ -95,47 +128,80 @@ func extractPackagesInfo(pkgs []*rpm.Package) *data.Info {
 	info := &data.Info{
 		Users:  make(map[string]*data.User),
 		Groups: make(map[string]*data.Group),
+		Units:  make(map[string]*systemd.Unit),
+
+		PCModules: make(map[string]*data.PkgConfig),
 	}
 
+	configPorts := make(map[string][]*data.Port)
+
 	for _, pkg := range pkgs {
-		addPackageInfo(info, pkg)
+		addPackageInfo(info, pkg, configPorts)
 	}
 
+	addDepsInfo(info, pkgs)
+	addPortsInfo(info, configPorts)
+	checkELFRPaths(info)
+	checkPythonScripts(info)
+	setLuaRuntimes(info)
+	checkKernelModules(info)
+
+	info.Distro = distro.Parse(info.Dist)
+
 	return info
 }
 
 // addPackageInfo extracts info from package
-func addPackageInfo(info *data.Info, pkg *rpm.Package) {
+func addPackageInfo(info *data.Info, pkg *rpm.Package, configPorts map[string][]*data.Port) {
+	payload := &payloadReader{}
+
 	info.Pkgs = append(info.Pkgs, pkg.Name)
+	info.Packages = append(info.Packages, pkg)
 	info.Dist = pkg.Dist
 
+	addAlternativesInfo(info, pkg)
 	addAppsInfo(info, pkg)
 	addConfigsInfo(info, pkg)
 	addCompletions(info, pkg)
 	addLibsInfo(info, pkg)
+	addELFInfo(info, pkg, payload)
 	addHeadersInfo(info, pkg)
-	addPkgConfigsInfo(info, pkg)
+	addPkgConfigsInfo(info, pkg, payload)
 	addOwnersInfo(info, pkg)
-	addServicesInfo(info, pkg)
+	addServicesInfo(info, pkg, payload)
+	addSysConfigsInfo(info, pkg, payload)
 	addPython2ModulesInfo(info, pkg)
 	addPython3ModulesInfo(info, pkg)
 	addPythonWheels(info, pkg)
+	addPythonDistsInfo(info, pkg, payload)
+	addPerlModulesInfo(info, pkg)
+	addRubyGemsInfo(info, pkg)
+	addNodeModulesInfo(info, pkg, payload)
+	addPHPExtensionsInfo(info, pkg, payload)
+	addLuaModulesInfo(info, pkg)
+	addKernelInfo(info, pkg)
+	addConfigPortsInfo(configPorts, pkg, payload)
+
+	payload.Flush(info, pkg)
 
 	sort.Strings(info.Pkgs)
-	sort.Strings(info.Apps)
 	sort.Strings(info.PkgConfigs)
 	sort.Strings(info.SharedLibs)
 	sort.Strings(info.Headers)
+	sort.Strings(info.HeaderFiles)
 	sort.Strings(info.Services)
 	sort.Strings(info.Python2Modules)
 	sort.Strings(info.Python3Modules)
 
 	info.Services = slices.Compact(info.Services)
 
-	if pkg.Scriptlets != "" {
-		extractUsersData(pkg.Scriptlets, info.Users)
-		extractGroupsData(pkg.Scriptlets, info.Groups)
+	for _, scriptlet := range pkg.Scriptlets {
+		if isParsableScriptlet(scriptlet) {
+			extractAccountsData(scriptlet.Body, info.Users, info.Groups)
+		}
 	}
+
+	sort.Strings(info.Apps)
 }
 
 // addAppsInfo extracts info about applications from package info
@@ -144,7 +210,7 @@ func addAppsInfo(info *data.Info, pkg *rpm.Package) {
 		switch {
 		case obj.IsDir:
 			continue
-		case obj.Mode|0111 == 0:
+		case !obj.NoMeta && obj.Mode&0111 == 0:
 			continue
 		}
 
@@ -153,11 +219,28 @@ func addAppsInfo(info *data.Info, pkg *rpm.Package) {
 			strings.HasPrefix(obj.Path, "/usr/sbin/"),
 			strings.HasPrefix(obj.Path, "/bin/"),
 			strings.HasPrefix(obj.Path, "/sbin/"):
-			info.Apps = append(info.Apps, path.Base(obj.Path))
+			addApp(info, path.Base(obj.Path))
+		}
+	}
+
+	// Links in directories with binaries managed by alternatives are apps, even
+	// if they are not a part of package payload
+	for _, alt := range info.Alternatives {
+		for _, link := range append([]*data.Alternative{alt}, alt.Slaves...) {
+			if isBinDirObject(link.Link) {
+				addApp(info, path.Base(link.Link))
+			}
 		}
 	}
 }
 
+// addApp adds application with given name to info if it's not already added
+func addApp(info *data.Info, name string) {
+	if !slices.Contains(info.Apps, name) {
+		info.Apps = append(info.Apps, name)
+	}
+}
+
 // addConfigsInfo extracts info about configuration files from package info
 func addConfigsInfo(info *data.Info, pkg *rpm.Package) {
 	for _, obj := range pkg.Payload {
@@ -181,17 +264,6 @@ func addCompletions(info *data.Info, pkg *rpm.Package) {
 	}
 }
 
-// addPkgConfigsInfo extracts info about package configuration files
-// from package info
-func addPkgConfigsInfo(info *data.Info, pkg *rpm.Package) {
-	for _, obj := range pkg.Payload {
-		if matchAnyGlob(obj.Path, pkgConfigGlobs) {
-			cfgName := strutil.Exclude(PATH.Base(obj.Path), ".pc")
-			info.PkgConfigs = append(info.PkgConfigs, cfgName)
-		}
-	}
-}
-
 // addLibsInfo extracts info about libs from package info
 func addLibsInfo(info *data.Info, pkg *rpm.Package) {
 	for _, obj := range pkg.Payload {
@@ -217,6 +289,10 @@ func addHeadersInfo(info *data.Info, pkg *rpm.Package) {
 		headerDir := PATH.DirN(strutil.Exclude(obj.Path, includeDir+"/"), 1)
 
 		headers[headerDir] = true
+
+		if !obj.IsDir && strings.HasSuffix(obj.Path, ".h") {
+			info.HeaderFiles = append(info.HeaderFiles, strutil.Exclude(obj.Path, includeDir+"/"))
+		}
 	}
 
 	if len(headers) == 0 {
@@ -231,28 +307,69 @@ func addHeadersInfo(info *data.Info, pkg *rpm.Package) {
 // addOwnersInfo extracts info about users from package info
 func addOwnersInfo(info *data.Info, pkg *rpm.Package) {
 	for _, obj := range pkg.Payload {
-		if obj.User != "root" {
+		if obj.NoMeta {
+			continue
+		}
+
+		if obj.User != "root" && info.Users[obj.User] == nil {
 			info.Users[obj.User] = &data.User{Name: obj.User}
 		}
 
-		if obj.Group != "root" {
+		if obj.Group != "root" && info.Groups[obj.Group] == nil {
 			info.Groups[obj.Group] = &data.Group{Name: obj.Group}
 		}
 	}
 }
 
 // addServicesInfo extracts info about service from package info
-func addServicesInfo(info *data.Info, pkg *rpm.Package) {
+func addServicesInfo(info *data.Info, pkg *rpm.Package, payload *payloadReader) {
+	var units []string
+
 	for _, obj := range pkg.Payload {
 		if matchAnyGlob(obj.Path, systemdUnitGlobs) {
 			service := strutil.Exclude(path.Base(obj.Path), ".service")
 			info.Services = append(info.Services, service)
+
+			if !obj.IsLink {
+				units = append(units, obj.Path)
+			}
+		}
+
+		if matchAnyGlob(obj.Path, systemdExtraUnitGlobs) {
+			addUnitName(info, path.Base(obj.Path))
+
+			if !obj.IsLink {
+				units = append(units, obj.Path)
+			}
 		}
 
 		if strings.HasPrefix(obj.Path, "/etc/rc.d/init.d/") {
 			info.Services = append(info.Services, path.Base(obj.Path))
 		}
 	}
+
+	addUnitsInfo(info, units, payload)
+}
+
+// addUnitName adds name of socket, timer, path or target unit to info
+func addUnitName(info *data.Info, name string) {
+	switch path.Ext(name) {
+	case ".socket":
+		info.Sockets = append(info.Sockets, name)
+	case ".timer":
+		info.Timers = append(info.Timers, name)
+	case ".path":
+		info.PathUnits = append(info.PathUnits, name)
+	case ".target":
+		info.Targets = append(info.Targets, name)
+	}
+}
+
+// addUnitsInfo reads and parses systemd unit files from package payload
+func addUnitsInfo(info *data.Info, units []string, payload *payloadReader) {
+	payload.Read(units, func(file string, content []byte) {
+		info.Units[path.Base(file)] = systemd.Parse(path.Base(file), content)
+	}, nil)
 }
 
 // addPython2ModulesInfo adds info about Python 2 modules
@@ -262,7 +379,7 @@ func addPython2ModulesInfo(info *data.Info, pkg *rpm.Package) {
 	for _, obj := range pkg.Payload {
 		dir, ok := isPythonModuleObject(obj.Path, "2")
 
-		if !ok || strings.HasSuffix(obj.Path, ".egg-info") {
+		if !ok || isPythonMetadataObject(obj.Path) {
 			continue
 		}
 
@@ -288,7 +405,7 @@ func addPython3ModulesInfo(info *data.Info, pkg *rpm.Package) {
 	for _, obj := range pkg.Payload {
 		dir, ok := isPythonModuleObject(obj.Path, "3")
 
-		if !ok || strings.HasSuffix(obj.Path, ".egg-info") {
+		if !ok || isPythonMetadataObject(obj.Path) {
 			continue
 		}
 
@@ -302,6 +419,10 @@ func addPython3ModulesInfo(info *data.Info, pkg *rpm.Package) {
 			info.Python3Files = append(info.Python3Files, obj)
 			continue
 		}
+
+		if !obj.IsDir && pythonExtRegex.MatchString(obj.Path) {
+			info.Python3Exts = append(info.Python3Exts, obj.Path)
+		}
 	}
 
 	info.Python3Modules = append(info.Python3Modules, mapToSlice(modules)...)
@@ -316,188 +437,210 @@ func addPythonWheels(info *data.Info, pkg *rpm.Package) {
 	}
 }
 
-// isPackagesWithMixedDist returns true if given package set contains packages for
-// different OS versions
-func isPackagesWithMixedDist(pkgs []*rpm.Package) bool {
-	var dist string
+// addDepsInfo adds info about virtual provides, orders packages by their
+// dependencies and checks that packages in the set satisfy each other's
+// requirements
+func addDepsInfo(info *data.Info, pkgs []*rpm.Package) {
+	names := make(map[string]bool)
 
 	for _, pkg := range pkgs {
-		if dist != "" && dist != pkg.Dist {
-			return true
+		names[pkg.Name] = true
+	}
+
+	for _, pkg := range pkgs {
+		for _, provide := range pkg.Deps.Provides {
+			if provide.IsVirtual() && !names[provide.Name] {
+				info.VirtualProvides = append(info.VirtualProvides, provide.Name)
+			}
 		}
 
-		dist = pkg.Dist
+		unsatisfied := checkSiblingsDeps(pkg, pkgs)
+
+		info.UnsatisfiedDeps = append(info.UnsatisfiedDeps, unsatisfied...)
+		info.Warnings = append(info.Warnings, unsatisfied...)
 	}
 
-	return false
-}
+	sort.Strings(info.VirtualProvides)
 
-// formatLibName formats lib name to glob
-func formatLibName(file string) string {
-	basename := path.Base(file)
-	soIndex := strings.Index(basename, ".so.")
+	info.VirtualProvides = slices.Compact(info.VirtualProvides)
+	info.Packages = orderPackages(info.Packages)
+	info.Pkgs = nil
 
-	return strutil.Substring(basename, 0, soIndex) + ".so.*"
+	for _, pkg := range info.Packages {
+		info.Pkgs = append(info.Pkgs, pkg.Name)
+	}
 }
 
-// matchAnyGlob returns true if given string matches for any of given patterns
-func matchAnyGlob(name string, patterns []string) bool {
-	for _, pattern := range patterns {
-		match, _ := filepath.Match(pattern, name)
+// checkSiblingsDeps checks that requirements of given package on capabilities
+// provided by other packages from the set (package names, sonames, pkg-config
+// modules, virtual provides) are satisfied
+func checkSiblingsDeps(pkg *rpm.Package, pkgs []*rpm.Package) []string {
+	var result []string
 
-		if match {
-			return true
+	for _, require := range pkg.Deps.Requires {
+		if require.IsInternal() || require.IsRich() || isRequireSatisfied(require, pkg) {
+			continue
 		}
-	}
 
-	return false
-}
+		var providers []string
 
-// extractUsersData extracts lines with useradd commands from scriptles
-func extractUsersData(data string, users map[string]*data.User) {
-	lines := extractLines(data, "useradd")
+		for _, sibling := range pkgs {
+			if sibling == pkg || !isCapabilityProvided(require.Name, sibling) {
+				continue
+			}
 
-	for _, line := range lines {
-		user := parseUserAddCommand(line)
+			if isRequireSatisfied(require, sibling) {
+				providers = nil
+				break
+			}
 
-		if user != nil {
-			users[user.Name] = user
+			providers = append(providers, sibling.NEVRA())
+		}
+
+		if len(providers) != 0 {
+			result = append(result, fmt.Sprintf(
+				"%s requires %s, but given package set contains %s",
+				pkg.Name, require, strings.Join(providers, ", "),
+			))
 		}
 	}
+
+	return result
 }
 
-// extractGroupsData extracts lines with groupadd commands from scriptles
-func extractGroupsData(data string, groups map[string]*data.Group) {
-	lines := extractLines(data, "groupadd")
+// isCapabilityProvided returns true if given package provides capability with
+// given name with any version
+func isCapabilityProvided(name string, pkg *rpm.Package) bool {
+	for _, provide := range pkg.Deps.Provides {
+		if provide.Name == name {
+			return true
+		}
+	}
 
-	for _, line := range lines {
-		group := parseGroupAddCommand(line)
+	return false
+}
 
-		if group != nil {
-			groups[group.Name] = group
+// isRequireSatisfied returns true if given package provides required capability
+func isRequireSatisfied(require *rpm.Dependency, pkg *rpm.Package) bool {
+	for _, provide := range pkg.Deps.Provides {
+		if require.Matches(provide) {
+			return true
 		}
 	}
+
+	return false
 }
 
-// extractLines extracts lines with given command
-func extractLines(data, command string) []string {
-	r := strings.NewReader(data)
-	s := bufio.NewScanner(r)
+// orderPackages orders packages so that packages required by other packages
+// from the set go first
+func orderPackages(pkgs []*rpm.Package) []*rpm.Package {
+	sort.SliceStable(pkgs, func(i, j int) bool {
+		return pkgs[i].Name < pkgs[j].Name
+	})
 
-	var fullLine string
-	var result []string
+	var result []*rpm.Package
 
-	for s.Scan() {
-		line := s.Text()
+	added := make(map[*rpm.Package]bool)
 
-		if !strings.Contains(line, command+" ") && fullLine == "" {
-			continue
-		}
+	for len(result) < len(pkgs) {
+		var next *rpm.Package
 
-		if strings.HasSuffix(strings.Trim(line, " "), "\\") {
-			fullLine += strings.TrimRight(strings.Trim(line, " "), "\\")
-			continue
+		for _, pkg := range pkgs {
+			if !added[pkg] && !hasPendingDeps(pkg, pkgs, added) {
+				next = pkg
+				break
+			}
 		}
 
-		fullLine += strings.Trim(line, " ")
-
-		result = append(result, fullLine)
+		// Dependency loop, use the first package in alphabetical order
+		if next == nil {
+			for _, pkg := range pkgs {
+				if !added[pkg] {
+					next = pkg
+					break
+				}
+			}
+		}
 
-		fullLine = ""
+		added[next] = true
+		result = append(result, next)
 	}
 
 	return result
 }
 
-// parseUserAddCommand parses useradd command
-func parseUserAddCommand(command string) *data.User {
-	result := &data.User{}
-	gi := strings.Index(command, "useradd ")
-
-	if gi == -1 {
-		return nil
+// hasPendingDeps returns true if given package requires any package from the set
+// which is not added yet
+func hasPendingDeps(pkg *rpm.Package, pkgs []*rpm.Package, added map[*rpm.Package]bool) bool {
+	for _, require := range pkg.Deps.Requires {
+		for _, sibling := range pkgs {
+			if sibling == pkg || added[sibling] {
+				continue
+			}
+
+			if isRequireSatisfied(require, sibling) {
+				return true
+			}
+		}
 	}
 
-	command = command[gi+8:]
-
-	var isComment bool
+	return false
+}
 
-	for i := 0; i < 20; i++ {
-		option := strutil.ReadField(command, i, true, ' ')
+// splitPackagesByDist splits packages into groups with the same dist sorted by
+// dist name
+func splitPackagesByDist(pkgs []*rpm.Package) [][]*rpm.Package {
+	groups := make(map[string][]*rpm.Package)
 
-		if strings.Contains(option, "\"") || strings.Contains(option, "'") {
-			isComment = !isComment
-			continue
+	for _, pkg := range pkgs {
+		var dist string
+
+		// Packages for different minor versions of OS (e.g. el8 and el8_9)
+		// must be in the same group. Packages without known dist tag are
+		// placed in a single group, because dist extracted from their release
+		// is just a part of release.
+		if d := distro.Parse(pkg.Dist); d.IsKnown() {
+			dist = d.Suffix()
 		}
 
-		if isComment {
-			continue
-		}
+		groups[dist] = append(groups[dist], pkg)
+	}
 
-		switch option {
-		case "-D", "--defaults", "-m", "--create-home", "-l", "--no-log-init",
-			"-M", "--no-create-home", "-N", "--no-user-group", "-o", "--non-unique",
-			"-r", "--system", "-U", "--user-group", "-c", "--comment":
-			continue // ignore option
-		case "-e", "--expiredate", "-f", "--inactive",
-			"-k", "--skel", "-K", "--key", "-p", "--password", "-R", "--root",
-			"-P", "--prefix", "-Z", "--selinux-user":
-			i++
-			continue // ignore option and value
-		case "-d", "--home-dir":
-			result.Home = strutil.ReadField(command, i+1, true, ' ')
-			i++
-		case "-g", "--gid":
-			result.GID = strutil.ReadField(command, i+1, true, ' ')
-			i++
-		case "-u", "--uid":
-			result.UID = strutil.ReadField(command, i+1, true, ' ')
-			i++
-		case "-s", "--shell":
-			result.Shell = strutil.ReadField(command, i+1, true, ' ')
-			i++
-		case "-G", "--groups":
-			result.Group = strutil.ReadField(command, i+1, true, ' ')
-			i++
-		default:
-			result.Name = option
-			return result
-		}
+	var result [][]*rpm.Package
+
+	for _, dist := range slices.Sorted(maps.Keys(groups)) {
+		result = append(result, groups[dist])
 	}
 
 	return result
 }
 
-// parseGroupAddCommand parses groupadd command
-func parseGroupAddCommand(command string) *data.Group {
-	result := &data.Group{}
-	gi := strings.Index(command, "groupadd ")
+// isParsableScriptlet returns true if scriptlet is executed by shell on package
+// install. Scriptlets which are executed only on package erase and scriptlets
+// for other interpreters (e.g. <lua>) are ignored.
+func isParsableScriptlet(scriptlet *rpm.Scriptlet) bool {
+	return scriptlet.IsInstall() && scriptlet.IsShell() && scriptlet.Body != ""
+}
 
-	if gi == -1 {
-		return nil
-	}
+// formatLibName formats lib name to glob
+func formatLibName(file string) string {
+	basename := path.Base(file)
+	soIndex := strings.Index(basename, ".so.")
 
-	command = command[gi+9:]
+	return strutil.Substring(basename, 0, soIndex) + ".so.*"
+}
 
-	for i := 0; i < 10; i++ {
-		option := strutil.ReadField(command, i, true, ' ')
+// matchAnyGlob returns true if given string matches for any of given patterns
+func matchAnyGlob(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		match, _ := filepath.Match(pattern, name)
 
-		switch option {
-		case "-f", "--force", "-o", "-non-unique", "-r", "--system":
-			continue // ignore option
-		case "-K", "--key", "-p", "--password", "-R", "--root", "-P", "--prefix":
-			i++
-			continue // ignore option and value
-		case "-g", "--gid":
-			result.GID = strutil.ReadField(command, i+1, true, ' ')
-			i++
-		default:
-			result.Name = option
-			return result
+		if match {
+			return true
 		}
 	}
 
-	return result
+	return false
 }
 
 // isPythonModuleObject checks if given object is a part of Python module
@@ -514,6 +657,12 @@ func isPythonModuleObject(path, version string) (string, bool) {
 	return "", false
 }
 
+// isPythonMetadataObject returns true if given object is a part of dist-info or
+// egg-info metadata
+func isPythonMetadataObject(path string) bool {
+	return strings.Contains(path+"/", ".egg-info/") || strings.Contains(path+"/", ".dist-info/")
+}
+
 // extractPythonModuleName extracts module name from path
 func extractPythonModuleName(path, dir string) string {
 	path = strutil.Exclude(path, dir+"/site-packages/")
@@ -0,0 +1,471 @@
+package extractor
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"testing"
+
+	"github.com/essentialkaos/bop/data"
+	"github.com/essentialkaos/bop/rpm"
+
+	. "github.com/essentialkaos/check"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func Test(t *testing.T) { TestingT(t) }
+
+type ExtractorSuite struct{}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var _ = Suite(&ExtractorSuite{})
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// testPackage contains info about package for in-memory reader
+type testPackage struct {
+	Pkg      *rpm.Package
+	Contents map[string]string
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (s *ExtractorSuite) TestContentExtractors(c *C) {
+	tests := []struct {
+		Name  string
+		Files []string
+		Pkgs  []testPackage
+		Check func(c *C, info *data.Info)
+	}{
+		{
+			Name: "systemd units",
+			Pkgs: []testPackage{{
+				Pkg: genTestPackage("app", genTestFile("/usr/lib/systemd/system/app.service")),
+				Contents: map[string]string{
+					"/usr/lib/systemd/system/app.service": "[Service]\nUser=app\nGroup=app\nExecStart=/usr/bin/app\n",
+				},
+			}},
+			Check: func(c *C, info *data.Info) {
+				c.Assert(info.Services, DeepEquals, []string{"app"})
+				c.Assert(info.Units["app.service"], NotNil)
+				c.Assert(info.Units["app.service"].Service, NotNil)
+				c.Assert(info.Units["app.service"].Service.User, Equals, "app")
+			},
+		},
+		{
+			Name: "sysusers.d and tmpfiles.d",
+			Pkgs: []testPackage{{
+				Pkg: genTestPackage("app",
+					genTestFile("/usr/lib/sysusers.d/app.conf"),
+					genTestFile("/usr/lib/tmpfiles.d/app.conf"),
+				),
+				Contents: map[string]string{
+					"/usr/lib/sysusers.d/app.conf": "g app 500\nu app 500:app \"App\" /var/lib/app /sbin/nologin\n",
+					"/usr/lib/tmpfiles.d/app.conf": "d /run/app 0750 app app -\n",
+				},
+			}},
+			Check: func(c *C, info *data.Info) {
+				c.Assert(info.Users["app"], NotNil)
+				c.Assert(info.Users["app"].UID, Equals, "500")
+				c.Assert(info.Users["app"].Home, Equals, "/var/lib/app")
+				c.Assert(info.Groups["app"], NotNil)
+				c.Assert(info.Groups["app"].GID, Equals, "500")
+				c.Assert(info.RuntimeDirs, HasLen, 1)
+				c.Assert(info.RuntimeDirs[0].Path, Equals, "/run/app")
+			},
+		},
+		{
+			Name: "sysusers.d users with implicit groups and ID ranges",
+			Pkgs: []testPackage{{
+				Pkg: genTestPackage("app", genTestFile("/usr/lib/sysusers.d/app.conf")),
+				Contents: map[string]string{
+					"/usr/lib/sysusers.d/app.conf": "r - 500-900\nu app 600 \"App\"\nu web 601:602 \"Web\"\n",
+				},
+			}},
+			Check: func(c *C, info *data.Info) {
+				c.Assert(info.Users["app"].UID, Equals, "600")
+				c.Assert(info.Users["app"].GID, Equals, "")
+				c.Assert(info.Users["app"].Group, Equals, "app")
+				c.Assert(info.Groups["app"].GID, Equals, "")
+				c.Assert(info.Users["web"].GID, Equals, "602")
+				c.Assert(info.Warnings, DeepEquals, []string{
+					"/usr/lib/sysusers.d/app.conf defines range 500-900 for allocating IDs of users and groups, IDs allocated from this range are not checked",
+				})
+			},
+		},
+		{
+			Name: "pkg-config files",
+			Pkgs: []testPackage{{
+				Pkg: genTestPackage("foo-devel", genTestFile("/usr/lib64/pkgconfig/foo.pc")),
+				Contents: map[string]string{
+					"/usr/lib64/pkgconfig/foo.pc": "prefix=/usr\nlibdir=${prefix}/lib64\n\nName: foo\nVersion: 1.0\nRequires: zlib >= 1.2\nLibs: -L${libdir} -lfoo\n",
+				},
+			}},
+			Check: func(c *C, info *data.Info) {
+				c.Assert(info.PkgConfigs, DeepEquals, []string{"foo"})
+				c.Assert(info.PCModules["foo"], NotNil)
+				c.Assert(info.PCModules["foo"].Version, Equals, "1.0")
+				c.Assert(info.PCModules["foo"].Libs, DeepEquals, []string{"-lfoo"})
+				c.Assert(info.PCModules["foo"].Requires, HasLen, 1)
+				c.Assert(info.PCModules["foo"].Requires[0].Op, Equals, ">=")
+			},
+		},
+		{
+			Name: "Python distributions",
+			Pkgs: []testPackage{{
+				Pkg: genTestPackage("python3-foo",
+					genTestExecutable("/usr/bin/foo"),
+					genTestDir("/usr/lib/python3.11/site-packages/foo"),
+					genTestFile("/usr/lib/python3.11/site-packages/foo/__init__.py"),
+					genTestDir("/usr/lib/python3.11/site-packages/foo-1.0.dist-info"),
+					genTestFile("/usr/lib/python3.11/site-packages/foo-1.0.dist-info/METADATA"),
+					genTestFile("/usr/lib/python3.11/site-packages/foo-1.0.dist-info/entry_points.txt"),
+				),
+				Contents: map[string]string{
+					"/usr/lib/python3.11/site-packages/foo-1.0.dist-info/METADATA":         "Metadata-Version: 2.1\nName: foo\nVersion: 1.0\n\nDescription\n",
+					"/usr/lib/python3.11/site-packages/foo-1.0.dist-info/entry_points.txt": "[console_scripts]\nfoo = foo:main\nbar = foo:bar\n",
+				},
+			}},
+			Check: func(c *C, info *data.Info) {
+				c.Assert(info.Python3Modules, DeepEquals, []string{"foo"})
+				c.Assert(info.PythonDists, HasLen, 1)
+				c.Assert(info.PythonDists[0].Name, Equals, "foo")
+				c.Assert(info.PythonDists[0].Scripts, DeepEquals, []string{"foo"})
+				c.Assert(info.Apps, HasLen, 0)
+				c.Assert(info.Warnings, DeepEquals, []string{
+					"Console script bar from foo Python distribution is not packaged",
+				})
+			},
+		},
+		{
+			Name: "Node.js modules and PHP extensions",
+			Pkgs: []testPackage{{
+				Pkg: genTestPackage("modules",
+					genTestFile("/usr/lib/node_modules/left-pad/package.json"),
+					genTestFile("/etc/php.d/40-redis.ini"),
+					genTestFile("/usr/lib64/php/modules/redis.so"),
+					genTestFile("/usr/lib64/php/modules/igbinary.so"),
+				),
+				Contents: map[string]string{
+					"/usr/lib/node_modules/left-pad/package.json": `{"name": "left-pad", "version": "1.3.0", "main": "index.js"}`,
+					"/etc/php.d/40-redis.ini":                     "; Enable redis extension\nextension=redis.so\n",
+				},
+			}},
+			Check: func(c *C, info *data.Info) {
+				c.Assert(info.NodeModules, HasLen, 1)
+				c.Assert(info.NodeModules[0].Version, Equals, "1.3.0")
+				c.Assert(info.NodeModules[0].Loadable, Equals, true)
+				c.Assert(info.PHPExtensions, HasLen, 1)
+				c.Assert(info.PHPExtensions[0].Name, Equals, "redis")
+			},
+		},
+		{
+			Name: "accounts from scriptlets",
+			Pkgs: []testPackage{{
+				Pkg: addTestScriptlet(genTestPackage("app"), rpm.PHASE_PRE, "/bin/sh",
+					"getent group app >/dev/null || groupadd -r app\n"+
+						"getent passwd app >/dev/null || useradd -r -g app -d /var/lib/app -s /sbin/nologin -c \"App user\" app\n",
+				),
+			}},
+			Check: func(c *C, info *data.Info) {
+				c.Assert(info.Groups["app"], NotNil)
+				c.Assert(info.Users["app"], NotNil)
+				c.Assert(info.Users["app"].Home, Equals, "/var/lib/app")
+				c.Assert(info.Users["app"].Shell, Equals, "/sbin/nologin")
+			},
+		},
+		{
+			Name: "Debian adduser with group",
+			Pkgs: []testPackage{{
+				Pkg: addTestScriptlet(genTestPackage("app"), rpm.PHASE_PRE, "/bin/sh",
+					"adduser --system --group --home /var/lib/app app\n"+
+						"adduser app ssl-cert\n"+
+						"adduser www-data app\n",
+				),
+			}},
+			Check: func(c *C, info *data.Info) {
+				c.Assert(info.Users, HasLen, 1)
+				c.Assert(info.Users["app"], NotNil)
+				c.Assert(info.Users["app"].Group, Equals, "ssl-cert")
+				c.Assert(info.Users["ssl-cert"], IsNil)
+				c.Assert(info.Groups["ssl-cert"], IsNil)
+			},
+		},
+		{
+			Name: "supplementary groups",
+			Pkgs: []testPackage{{
+				Pkg: addTestScriptlet(genTestPackage("app"), rpm.PHASE_PRE, "/bin/sh",
+					"useradd -r -g app app\n"+
+						"usermod -aG wheel app\n"+
+						"gpasswd -a app adm\n"+
+						"usermod -aG app apache\n"+
+						"gpasswd -a nginx app\n",
+				),
+			}},
+			Check: func(c *C, info *data.Info) {
+				c.Assert(info.Users, HasLen, 1)
+				c.Assert(info.Users["app"].Group, Equals, "app,wheel,adm")
+				c.Assert(info.Users["apache"], IsNil)
+				c.Assert(info.Users["nginx"], IsNil)
+			},
+		},
+		{
+			Name: "non-shell scriptlets",
+			Pkgs: []testPackage{{
+				Pkg: addTestScriptlet(genTestPackage("app"), rpm.PHASE_PRE, "<lua>", "useradd -r app"),
+			}},
+			Check: func(c *C, info *data.Info) {
+				c.Assert(info.Users, HasLen, 0)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		c.Log(test.Name)
+
+		infos, err := ProcessPackagesByDist(registerTestPackages(test.Pkgs))
+
+		c.Assert(err, IsNil)
+		c.Assert(infos, HasLen, 1)
+
+		test.Check(c, infos[0])
+	}
+}
+
+func (s *ExtractorSuite) TestSplitByDist(c *C) {
+	var pkgs []testPackage
+
+	for _, dist := range []string{"el8", "el8_9", "el9"} {
+		pkg := genTestPackage("app-"+dist, genTestExecutable("/usr/bin/app"))
+		pkg.Release, pkg.Dist = "1."+dist, dist
+		pkgs = append(pkgs, testPackage{Pkg: pkg})
+	}
+
+	infos, err := ProcessPackagesByDist(registerTestPackages(pkgs))
+
+	c.Assert(err, IsNil)
+	c.Assert(infos, HasLen, 2)
+	c.Assert(infos[0].Pkgs, DeepEquals, []string{"app-el8", "app-el8_9"})
+	c.Assert(infos[1].Pkgs, DeepEquals, []string{"app-el9"})
+
+	// Packages without dist tag in release
+	pkgs = nil
+
+	for _, release := range []string{"1", "2", "3.1"} {
+		pkg := genTestPackage("app-"+release, genTestExecutable("/usr/bin/app"))
+		pkg.Release, pkg.Dist = release, rpm.ExtractDist(release)
+		pkgs = append(pkgs, testPackage{Pkg: pkg})
+	}
+
+	infos, err = ProcessPackagesByDist(registerTestPackages(pkgs))
+
+	c.Assert(err, IsNil)
+	c.Assert(infos, HasLen, 1)
+	c.Assert(infos[0].Pkgs, DeepEquals, []string{"app-1", "app-2", "app-3.1"})
+}
+
+func (s *ExtractorSuite) TestSiblingsDeps(c *C) {
+	lib := genTestPackage("libfoo")
+	lib.Deps.Provides = []*rpm.Dependency{
+		{Name: "libfoo", Version: "1.0-1.el9", Flags: rpm.SENSE_EQUAL},
+		{Name: "libfoo.so.1()(64bit)"},
+		{Name: "pkgconfig(foo)", Version: "1.0", Flags: rpm.SENSE_EQUAL},
+	}
+
+	app := genTestPackage("app")
+	app.Deps.Requires = []*rpm.Dependency{
+		{Name: "libfoo.so.1()(64bit)"},
+		{Name: "pkgconfig(foo)", Version: "2.0", Flags: rpm.SENSE_GREATER | rpm.SENSE_EQUAL},
+		{Name: "libbar.so.1()(64bit)"},
+		{Name: "rpmlib(CompressedFileNames)", Version: "3.0.4-1", Flags: rpm.SENSE_RPMLIB},
+	}
+
+	infos, err := ProcessPackagesByDist(registerTestPackages([]testPackage{{Pkg: app}, {Pkg: lib}}))
+
+	c.Assert(err, IsNil)
+	c.Assert(infos, HasLen, 1)
+	c.Assert(infos[0].Pkgs, DeepEquals, []string{"libfoo", "app"})
+	c.Assert(infos[0].UnsatisfiedDeps, DeepEquals, []string{
+		"app requires pkgconfig(foo) >= 2.0, but given package set contains libfoo-1.0-1.el9.x86_64",
+	})
+}
+
+func (s *ExtractorSuite) TestMetadataOnlyPackages(c *C) {
+	// Content of files isn't available, so extractors must use only info
+	// from payload
+	reader := rpm.NewMemoryReader().Add("app.rpm", genTestPackage("app",
+		&rpm.Object{Path: "/usr/bin/app", NoMeta: true},
+		&rpm.Object{Path: "/usr/lib/systemd/system/app.service", NoMeta: true},
+		&rpm.Object{Path: "/usr/lib64/pkgconfig/app.pc", NoMeta: true},
+	))
+
+	rpm.SetReaders(&noContentReader{reader})
+
+	infos, err := ProcessPackagesByDist([]string{"app.rpm"})
+
+	c.Assert(err, IsNil)
+	c.Assert(infos, HasLen, 1)
+	c.Assert(infos[0].Apps, DeepEquals, []string{"app"})
+	c.Assert(infos[0].Services, DeepEquals, []string{"app"})
+	c.Assert(infos[0].Users, HasLen, 0)
+	c.Assert(infos[0].PkgConfigs, DeepEquals, []string{"app"})
+	c.Assert(infos[0].PCModules, HasLen, 0)
+	c.Assert(infos[0].Warnings, HasLen, 0)
+}
+
+func (s *ExtractorSuite) TestApps(c *C) {
+	app := addTestScriptlet(genTestPackage("app",
+		genTestExecutable("/usr/bin/app"),
+		genTestFile("/usr/bin/app-completion"),
+		genTestExecutable("/usr/libexec/app/app-worker"),
+		genTestExecutable("/usr/bin/app-client"),
+	), rpm.PHASE_POST, "/bin/sh",
+		"alternatives --install /usr/bin/app-cli app-cli /usr/bin/app-client 10 \\\n"+
+			"  --slave /usr/share/man/man1/app-cli.1.gz app-cli.1.gz /usr/share/man/man1/app-client.1.gz",
+	)
+
+	infos, err := ProcessPackagesByDist(registerTestPackages([]testPackage{{Pkg: app}}))
+
+	c.Assert(err, IsNil)
+	c.Assert(infos, HasLen, 1)
+	c.Assert(infos[0].Apps, DeepEquals, []string{"app", "app-cli", "app-client"})
+	c.Assert(infos[0].Alternatives, HasLen, 1)
+}
+
+func (s *ExtractorSuite) TestSinglePayloadPass(c *C) {
+	config := genTestFile("/etc/app.conf")
+	config.IsConfig = true
+
+	app := genTestPackage("app",
+		config,
+		genTestFile("/usr/lib/sysusers.d/app.conf"),
+		genTestFile("/usr/lib64/pkgconfig/app.pc"),
+	)
+
+	daemon := genTestPackage("app-daemon",
+		genTestFile("/usr/lib/systemd/system/app.service"),
+	)
+
+	reader := &countingReader{MemoryReader: rpm.NewMemoryReader(), Calls: make(map[string]int)}
+	files := addTestPackages(reader.MemoryReader, []testPackage{
+		{Pkg: app, Contents: map[string]string{
+			"/etc/app.conf":                "port 6379\n",
+			"/usr/lib/sysusers.d/app.conf": "u app - \"App\" /var/lib/app /sbin/nologin\n",
+			"/usr/lib64/pkgconfig/app.pc":  "Name: app\nVersion: 1.0\n",
+		}},
+		{Pkg: daemon, Contents: map[string]string{
+			"/usr/lib/systemd/system/app.service": "[Service]\nExecStart=/usr/bin/app\n",
+		}},
+	})
+
+	rpm.SetReaders(reader)
+
+	infos, err := ProcessPackagesByDist(files)
+
+	c.Assert(err, IsNil)
+	c.Assert(infos, HasLen, 1)
+	c.Assert(reader.Calls, DeepEquals, map[string]int{files[0]: 1, files[1]: 1})
+	c.Assert(infos[0].Units["app.service"], NotNil)
+	c.Assert(infos[0].Users["app"], NotNil)
+	c.Assert(infos[0].PCModules["app"], NotNil)
+	c.Assert(infos[0].Ports["app"], DeepEquals, []*data.Port{{Port: 6379}})
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// noContentReader is in-memory reader without content of payload files
+type noContentReader struct {
+	*rpm.MemoryReader
+}
+
+// ReadFiles returns error because content is not available
+func (r *noContentReader) ReadFiles(file string, paths []string, handler rpm.FileHandler) error {
+	return rpm.ErrNoContent
+}
+
+// countingReader is in-memory reader which counts reads of payload
+type countingReader struct {
+	*rpm.MemoryReader
+	Calls map[string]int
+}
+
+// ReadFiles reads content of files and counts calls for every package
+func (r *countingReader) ReadFiles(file string, paths []string, handler rpm.FileHandler) error {
+	r.Calls[file]++
+	return r.MemoryReader.ReadFiles(file, paths, handler)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// registerTestPackages registers in-memory reader with given packages and
+// returns their file names
+func registerTestPackages(pkgs []testPackage) []string {
+	reader := rpm.NewMemoryReader()
+	files := addTestPackages(reader, pkgs)
+
+	rpm.SetReaders(reader)
+
+	return files
+}
+
+// addTestPackages adds given packages to in-memory reader and returns their
+// file names
+func addTestPackages(reader *rpm.MemoryReader, pkgs []testPackage) []string {
+	var files []string
+
+	for _, p := range pkgs {
+		file := p.Pkg.NEVRA() + ".rpm"
+		contents := make(map[string][]byte)
+
+		for path, content := range p.Contents {
+			contents[path] = []byte(content)
+		}
+
+		reader.Add(file, p.Pkg).AddContent(file, contents)
+		files = append(files, file)
+	}
+
+	return files
+}
+
+// genTestPackage generates package with given payload
+func genTestPackage(name string, payload ...*rpm.Object) *rpm.Package {
+	return &rpm.Package{
+		Name:    name,
+		Version: "1.0",
+		Release: "1.el9",
+		Arch:    "x86_64",
+		Dist:    "el9",
+		Payload: payload,
+	}
+}
+
+// addTestScriptlet adds scriptlet to package
+func addTestScriptlet(pkg *rpm.Package, phase, interpreter, body string) *rpm.Package {
+	pkg.Scriptlets = append(pkg.Scriptlets, &rpm.Scriptlet{
+		Phase: phase, Interpreter: interpreter, Body: body,
+	})
+
+	return pkg
+}
+
+// genTestFile generates payload object for regular file
+func genTestFile(path string) *rpm.Object {
+	return &rpm.Object{Path: path, User: "root", Group: "root", Mode: 0644}
+}
+
+// genTestExecutable generates payload object for executable file
+func genTestExecutable(path string) *rpm.Object {
+	return &rpm.Object{Path: path, User: "root", Group: "root", Mode: 0755}
+}
+
+// genTestDir generates payload object for directory
+func genTestDir(path string) *rpm.Object {
+	return &rpm.Object{Path: path, User: "root", Group: "root", Mode: 0755, IsDir: true}
+}
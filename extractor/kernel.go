@@ -0,0 +1,90 @@
+package extractor
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/essentialkaos/bop/data"
+	"github.com/essentialkaos/bop/rpm"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// MAX_KERNEL_MODULES is maximum number of kernel modules which will be checked
+const MAX_KERNEL_MODULES = 32
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// kernelModuleRegex is regex for kernel module path
+var kernelModuleRegex = regexp.MustCompile(`^(?:/usr)?/lib/modules/([^/]+)/.+/([^/]+)\.ko(?:\.xz|\.zst|\.gz)?$`)
+
+var udevRulesGlobs = []string{
+	"/usr/lib/udev/rules.d/*.rules",
+	"/lib/udev/rules.d/*.rules",
+	"/etc/udev/rules.d/*.rules",
+}
+
+var modprobeConfsGlobs = []string{
+	"/usr/lib/modprobe.d/*.conf",
+	"/lib/modprobe.d/*.conf",
+	"/etc/modprobe.d/*.conf",
+	"/usr/lib/modules-load.d/*.conf",
+	"/etc/modules-load.d/*.conf",
+}
+
+var dracutModulesGlobs = []string{
+	"/usr/lib/dracut/modules.d/*",
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// addKernelInfo adds info about kernel modules, udev rules, modprobe
+// configuration and dracut modules
+func addKernelInfo(info *data.Info, pkg *rpm.Package) {
+	for _, obj := range pkg.Payload {
+		match := kernelModuleRegex.FindStringSubmatch(obj.Path)
+
+		switch {
+		case obj.IsLink:
+			continue
+		case obj.IsDir && matchAnyGlob(obj.Path, dracutModulesGlobs):
+			info.DracutModules = append(info.DracutModules, obj)
+		case obj.IsDir:
+			continue
+		case obj.IsConfig:
+			// Configuration files are checked separately
+			continue
+		case matchAnyGlob(obj.Path, udevRulesGlobs):
+			info.UdevRules = append(info.UdevRules, obj)
+		case matchAnyGlob(obj.Path, modprobeConfsGlobs):
+			info.ModprobeConfs = append(info.ModprobeConfs, obj)
+		case match != nil:
+			info.KernelModules = append(info.KernelModules, &data.KernelModule{
+				Name:          match[2],
+				KernelVersion: match[1],
+				File:          obj,
+			})
+		}
+	}
+}
+
+// checkKernelModules limits number of checked kernel modules
+func checkKernelModules(info *data.Info) {
+	if len(info.KernelModules) <= MAX_KERNEL_MODULES {
+		return
+	}
+
+	info.Warnings = append(info.Warnings, fmt.Sprintf(
+		"Packages contain %d kernel modules, only the first %d will be checked",
+		len(info.KernelModules), MAX_KERNEL_MODULES,
+	))
+
+	info.KernelModules = info.KernelModules[:MAX_KERNEL_MODULES]
+}
@@ -0,0 +1,312 @@
+package extractor
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strings"
+
+	PATH "github.com/essentialkaos/ek/v13/path"
+	"github.com/essentialkaos/ek/v13/strutil"
+
+	"github.com/essentialkaos/bop/data"
+	"github.com/essentialkaos/bop/rpm"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// perlDirs contains Perl modules directories (vendor directories go first)
+var perlDirs = []string{
+	"/usr/share/perl5/vendor_perl/",
+	"/usr/lib64/perl5/vendor_perl/",
+	"/usr/lib/perl5/vendor_perl/",
+	"/usr/share/perl5/",
+	"/usr/lib64/perl5/",
+	"/usr/lib/perl5/",
+}
+
+var rubyGemsGlobs = []string{
+	"/usr/share/gems/specifications/*.gemspec",
+}
+
+var nodeModulesGlobs = []string{
+	"/usr/lib/node_modules/*/package.json",
+	"/usr/lib/node_modules/@*/*/package.json",
+}
+
+var phpConfigGlobs = []string{
+	"/etc/php.d/*.ini",
+}
+
+var phpExtensionsGlobs = []string{
+	"/usr/lib64/php/modules/*.so",
+	"/usr/lib/php/modules/*.so",
+}
+
+// luaModuleRegex is regex for Lua module path
+var luaModuleRegex = regexp.MustCompile(`^/usr/(?:share|lib|lib64)/lua/([0-9.]+)/([^/]+?)(?:\.lua|\.so|/init\.lua)$`)
+
+// phpExtensionRegex is regex for extension loading directive in PHP configuration
+var phpExtensionRegex = regexp.MustCompile(`^\s*extension\s*=\s*["']?([^"'\s;]+)`)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// addPerlModulesInfo adds info about Perl modules. Only main (the least nested)
+// modules are used for checks.
+func addPerlModulesInfo(info *data.Info, pkg *rpm.Package) {
+	var modules []*data.Module
+
+	minDepth := -1
+
+	for _, obj := range pkg.Payload {
+		if obj.IsDir || obj.IsLink || !strings.HasSuffix(obj.Path, ".pm") {
+			continue
+		}
+
+		dir := getPerlDir(obj.Path)
+
+		if dir == "" || strings.Contains(obj.Path, "/auto/") {
+			continue
+		}
+
+		module := strings.TrimSuffix(strings.TrimPrefix(obj.Path, dir), ".pm")
+		depth := strings.Count(module, "/")
+
+		switch {
+		case minDepth == -1 || depth < minDepth:
+			minDepth, modules = depth, nil
+		case depth > minDepth:
+			continue
+		}
+
+		modules = append(modules, &data.Module{
+			Name:     strings.ReplaceAll(module, "/", "::"),
+			Path:     obj.Path,
+			Loadable: true,
+		})
+	}
+
+	info.PerlModules = append(info.PerlModules, modules...)
+}
+
+// addRubyGemsInfo adds info about Ruby gems
+func addRubyGemsInfo(info *data.Info, pkg *rpm.Package) {
+	for _, obj := range pkg.Payload {
+		if obj.IsDir || !matchAnyGlob(obj.Path, rubyGemsGlobs) {
+			continue
+		}
+
+		name, version := splitGemName(strings.TrimSuffix(PATH.Base(obj.Path), ".gemspec"))
+
+		info.RubyGems = append(info.RubyGems, &data.Module{
+			Name:     name,
+			Version:  version,
+			Path:     obj.Path,
+			Loadable: true,
+		})
+	}
+}
+
+// addNodeModulesInfo adds info about Node.js modules
+func addNodeModulesInfo(info *data.Info, pkg *rpm.Package, payload *payloadReader) {
+	var modules []*data.Module
+	var files []string
+
+	objects := make(map[string]bool)
+
+	for _, obj := range pkg.Payload {
+		objects[obj.Path] = true
+	}
+
+	for _, obj := range pkg.Payload {
+		if obj.IsDir || !matchAnyGlob(obj.Path, nodeModulesGlobs) {
+			continue
+		}
+
+		dir := PATH.Dir(obj.Path)
+
+		modules = append(modules, &data.Module{
+			Name:     strings.TrimPrefix(dir, "/usr/lib/node_modules/"),
+			Path:     dir,
+			Loadable: objects[dir+"/index.js"],
+		})
+
+		if !obj.IsLink {
+			files = append(files, obj.Path)
+		}
+	}
+
+	info.NodeModules = append(info.NodeModules, modules...)
+
+	payload.Collect(files, func(contents map[string][]byte) {
+		for _, module := range modules {
+			content := contents[module.Path+"/package.json"]
+
+			if content == nil {
+				continue
+			}
+
+			pkgInfo := struct {
+				Version string `json:"version"`
+				Main    string `json:"main"`
+				Exports any    `json:"exports"`
+			}{}
+
+			if json.Unmarshal(content, &pkgInfo) != nil {
+				continue
+			}
+
+			module.Version = pkgInfo.Version
+			module.Loadable = module.Loadable || pkgInfo.Main != "" || pkgInfo.Exports != nil
+		}
+	})
+}
+
+// addPHPExtensionsInfo adds info about PHP extensions
+func addPHPExtensionsInfo(info *data.Info, pkg *rpm.Package, payload *payloadReader) {
+	var configs, extensions []string
+
+	for _, obj := range pkg.Payload {
+		switch {
+		case obj.IsDir || obj.IsLink:
+			continue
+		case matchAnyGlob(obj.Path, phpConfigGlobs):
+			configs = append(configs, obj.Path)
+		case matchAnyGlob(obj.Path, phpExtensionsGlobs):
+			extensions = append(extensions, strutil.Exclude(PATH.Base(obj.Path), ".so"))
+		}
+	}
+
+	if len(extensions) == 0 {
+		return
+	}
+
+	if len(configs) == 0 {
+		addPHPExtensions(info, extensions)
+		return
+	}
+
+	// Use configuration files for getting the list of enabled extensions if
+	// their content is available
+	contents := make(map[string][]byte)
+
+	payload.Read(
+		configs,
+		func(path string, data []byte) { contents[path] = data },
+		func(ok bool) {
+			if ok {
+				extensions = nil
+
+				for _, config := range configs {
+					extensions = append(extensions, parsePHPConfig(contents[config])...)
+				}
+			}
+
+			addPHPExtensions(info, extensions)
+		},
+	)
+}
+
+// addPHPExtensions adds PHP extensions with given names to info
+func addPHPExtensions(info *data.Info, extensions []string) {
+	for _, ext := range extensions {
+		if !slices.ContainsFunc(info.PHPExtensions, func(m *data.Module) bool { return m.Name == ext }) {
+			info.PHPExtensions = append(info.PHPExtensions, &data.Module{Name: ext, Loadable: true})
+		}
+	}
+}
+
+// addLuaModulesInfo adds info about Lua modules
+func addLuaModulesInfo(info *data.Info, pkg *rpm.Package) {
+	for _, obj := range pkg.Payload {
+		if obj.IsDir {
+			continue
+		}
+
+		match := luaModuleRegex.FindStringSubmatch(obj.Path)
+
+		if match == nil {
+			continue
+		}
+
+		info.LuaModules = append(info.LuaModules, &data.Module{
+			Name:     match[2],
+			Version:  match[1],
+			Path:     obj.Path,
+			Runtime:  "lua",
+			Loadable: true,
+		})
+	}
+}
+
+// setLuaRuntimes sets interpreters for Lua modules if packages contain
+// modules for several versions of Lua
+func setLuaRuntimes(info *data.Info) {
+	var versions []string
+
+	for _, module := range info.LuaModules {
+		if !slices.Contains(versions, module.Version) {
+			versions = append(versions, module.Version)
+		}
+	}
+
+	if len(versions) < 2 {
+		return
+	}
+
+	for _, module := range info.LuaModules {
+		module.Runtime = "lua-" + module.Version
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// getPerlDir returns Perl modules directory for given path
+func getPerlDir(path string) string {
+	for _, dir := range perlDirs {
+		if strings.HasPrefix(path, dir) {
+			return dir
+		}
+	}
+
+	return ""
+}
+
+// splitGemName splits gem specification name into name and version
+// (e.g. net-http-0.4.1 → net-http and 0.4.1)
+func splitGemName(name string) (string, string) {
+	index := strings.LastIndex(name, "-")
+
+	if index == -1 || index == len(name)-1 || name[index+1] < '0' || name[index+1] > '9' {
+		return name, ""
+	}
+
+	return name[:index], name[index+1:]
+}
+
+// parsePHPConfig parses PHP configuration and returns names of enabled
+// extensions
+func parsePHPConfig(config []byte) []string {
+	var result []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(config))
+
+	for scanner.Scan() {
+		match := phpExtensionRegex.FindStringSubmatch(scanner.Text())
+
+		if match != nil {
+			result = append(result, strings.TrimSuffix(PATH.Base(match[1]), ".so"))
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,97 @@
+package extractor
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/essentialkaos/bop/data"
+	"github.com/essentialkaos/bop/rpm"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// payloadReader collects files requested by extractors and reads their content
+// in a single pass over package payload
+type payloadReader struct {
+	handlers map[string][]func(path string, data []byte)
+	done     []func(ok bool)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Read requests content of given files. Handler is called for every file while
+// payload is read, so content can be dropped right after processing. Done is
+// called after reading the whole payload; ok is false if content of package is
+// not available or can't be read.
+func (r *payloadReader) Read(files []string, handler func(path string, data []byte), done func(ok bool)) {
+	if len(files) == 0 {
+		return
+	}
+
+	if r.handlers == nil {
+		r.handlers = make(map[string][]func(path string, data []byte))
+	}
+
+	for _, file := range files {
+		r.handlers[file] = append(r.handlers[file], handler)
+	}
+
+	if done != nil {
+		r.done = append(r.done, done)
+	}
+}
+
+// Collect requests content of given files and passes it to handler after
+// reading the whole payload. Handler isn't called if content of package is not
+// available or can't be read.
+func (r *payloadReader) Collect(files []string, handler func(contents map[string][]byte)) {
+	contents := make(map[string][]byte)
+
+	r.Read(
+		files,
+		func(path string, data []byte) { contents[path] = data },
+		func(ok bool) {
+			if ok {
+				handler(contents)
+			}
+		},
+	)
+}
+
+// Flush reads content of all requested files from package payload and calls
+// handlers
+func (r *payloadReader) Flush(info *data.Info, pkg *rpm.Package) {
+	if len(r.handlers) == 0 {
+		return
+	}
+
+	files := slices.Sorted(maps.Keys(r.handlers))
+
+	err := pkg.ReadFiles(files, func(path string, data []byte) error {
+		for _, handler := range r.handlers[path] {
+			handler(path, data)
+		}
+
+		return nil
+	})
+
+	if err != nil && err != rpm.ErrNoContent {
+		info.Warnings = append(info.Warnings, fmt.Sprintf(
+			"Can't read content of %s: %v", pkg.Name, err,
+		))
+	}
+
+	for _, done := range r.done {
+		done(err == nil)
+	}
+
+	r.handlers, r.done = nil, nil
+}
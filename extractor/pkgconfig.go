@@ -0,0 +1,146 @@
+package extractor
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	PATH "github.com/essentialkaos/ek/v13/path"
+	"github.com/essentialkaos/ek/v13/strutil"
+
+	"github.com/essentialkaos/bop/data"
+	"github.com/essentialkaos/bop/rpm"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var pkgConfigGlobs = []string{
+	"/usr/lib/pkgconfig/*.pc",
+	"/usr/lib64/pkgconfig/*.pc",
+	"/usr/share/pkgconfig/*.pc",
+}
+
+// pcVariableRegex is regex for variable references in pkg-config files
+var pcVariableRegex = regexp.MustCompile(`\$\{([a-zA-Z0-9_.]+)\}`)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// addPkgConfigsInfo extracts info about package configuration files
+// from package info
+func addPkgConfigsInfo(info *data.Info, pkg *rpm.Package, payload *payloadReader) {
+	var files []string
+
+	for _, obj := range pkg.Payload {
+		if matchAnyGlob(obj.Path, pkgConfigGlobs) {
+			cfgName := strutil.Exclude(PATH.Base(obj.Path), ".pc")
+			info.PkgConfigs = append(info.PkgConfigs, cfgName)
+
+			if !obj.IsLink && !obj.IsDir {
+				files = append(files, obj.Path)
+			}
+		}
+	}
+
+	payload.Collect(files, func(contents map[string][]byte) {
+		sort.Strings(files)
+
+		for _, file := range files {
+			pc := parsePkgConfig(contents[file])
+			pc.Name = strutil.Exclude(PATH.Base(file), ".pc")
+
+			if pc.Version != "" && pc.Version != pkg.Version {
+				info.Warnings = append(info.Warnings, fmt.Sprintf(
+					"Version of %s module in %s (%s) doesn't match package version (%s)",
+					pc.Name, PATH.Base(file), pc.Version, pkg.Version,
+				))
+			}
+
+			info.PCModules[pc.Name] = pc
+		}
+	})
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// parsePkgConfig parses pkg-config file
+func parsePkgConfig(content []byte) *data.PkgConfig {
+	result := &data.PkgConfig{}
+	vars := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		varIndex := strings.IndexRune(line, '=')
+		fieldIndex := strings.IndexRune(line, ':')
+
+		switch {
+		case varIndex > 0 && (fieldIndex == -1 || varIndex < fieldIndex):
+			name := strings.TrimSpace(line[:varIndex])
+			vars[name] = expandPCVariables(strings.TrimSpace(line[varIndex+1:]), vars)
+
+		case fieldIndex > 0:
+			value := expandPCVariables(strings.TrimSpace(line[fieldIndex+1:]), vars)
+
+			switch strings.TrimSpace(line[:fieldIndex]) {
+			case "Version":
+				result.Version = value
+			case "Requires":
+				result.Requires = parsePCRequires(value)
+			case "Libs":
+				for _, flag := range strings.Fields(value) {
+					if strings.HasPrefix(flag, "-l") && len(flag) > 2 {
+						result.Libs = append(result.Libs, flag)
+					}
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// parsePCRequires parses list of required modules (e.g. "glib-2.0 >= 2.50, zlib")
+func parsePCRequires(value string) []*data.PkgConfigRequire {
+	var result []*data.PkgConfigRequire
+
+	fields := strings.Fields(strings.ReplaceAll(value, ",", " "))
+
+	for i := 0; i < len(fields); i++ {
+		req := &data.PkgConfigRequire{Name: fields[i]}
+
+		if i+2 < len(fields) {
+			switch fields[i+1] {
+			case "=", "<", ">", "<=", ">=", "!=":
+				req.Op, req.Version = fields[i+1], fields[i+2]
+				i += 2
+			}
+		}
+
+		result = append(result, req)
+	}
+
+	return result
+}
+
+// expandPCVariables replaces variable references with their values
+func expandPCVariables(value string, vars map[string]string) string {
+	return pcVariableRegex.ReplaceAllStringFunc(value, func(ref string) string {
+		return vars[ref[2:len(ref)-1]]
+	})
+}
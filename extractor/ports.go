@@ -0,0 +1,137 @@
+package extractor
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bufio"
+	"bytes"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	PATH "github.com/essentialkaos/ek/v13/path"
+
+	"github.com/essentialkaos/bop/data"
+	"github.com/essentialkaos/bop/rpm"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// MAX_CONFIG_SIZE is maximum size of configuration file which will be parsed
+const MAX_CONFIG_SIZE = 512 * 1024
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// configPortRegex is regex for searching port options in configuration files
+var configPortRegex = regexp.MustCompile(`(?i)^\s*(?:port|listen|bind|http_port|listen_port)\s*(?:=|:|\s)\s*["']?([^\s;,"'#]+)`)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// addPortsInfo extracts info about ports used by services from socket units and
+// configuration files
+func addPortsInfo(info *data.Info, configPorts map[string][]*data.Port) {
+	if len(info.Services) == 0 {
+		return
+	}
+
+	info.Ports = make(data.PortMap)
+
+	for _, unit := range info.Units {
+		if unit.Socket == nil {
+			continue
+		}
+
+		service := strings.TrimSuffix(unit.Socket.Service, ".service")
+
+		for _, listen := range unit.Socket.ListenStream {
+			port := data.ParsePort(listen)
+
+			if port != nil {
+				info.Ports.Add(service, port)
+			}
+		}
+	}
+
+	for _, file := range slices.Sorted(maps.Keys(configPorts)) {
+		service := findConfigService(file, info.Services)
+
+		if service == "" {
+			continue
+		}
+
+		for _, port := range configPorts[file] {
+			info.Ports.Add(service, port)
+		}
+	}
+}
+
+// addConfigPortsInfo extracts info about ports from package configuration files.
+// Ports are bound to services after processing all packages, because service
+// can be provided by another package from the set.
+func addConfigPortsInfo(configPorts map[string][]*data.Port, pkg *rpm.Package, payload *payloadReader) {
+	var files []string
+
+	for _, obj := range pkg.Payload {
+		if obj.IsConfig && !obj.IsDir && !obj.IsLink && obj.Size <= MAX_CONFIG_SIZE {
+			files = append(files, obj.Path)
+		}
+	}
+
+	payload.Read(files, func(file string, content []byte) {
+		ports := parseConfigPorts(content)
+
+		if len(ports) != 0 {
+			configPorts[file] = ports
+		}
+	}, nil)
+}
+
+// findConfigService returns name of service which uses configuration file
+// with given path. Only main configuration files of daemons (e.g.
+// /etc/redis/redis.conf or /etc/sysconfig/redis) are used, because other
+// files can contain addresses of clients or upstream servers.
+func findConfigService(file string, services []string) string {
+	name := strings.TrimSuffix(PATH.Base(file), PATH.Ext(file))
+
+	for _, service := range services {
+		if strings.TrimSuffix(service, "@") == name {
+			return service
+		}
+	}
+
+	return ""
+}
+
+// parseConfigPorts extracts ports from configuration file data
+func parseConfigPorts(content []byte) []*data.Port {
+	var result []*data.Port
+
+	// Skip binary files
+	if bytes.IndexByte(content, 0) != -1 {
+		return nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+
+	for scanner.Scan() {
+		match := configPortRegex.FindStringSubmatch(scanner.Text())
+
+		if match == nil {
+			continue
+		}
+
+		port := data.ParsePort(match[1])
+
+		if port != nil {
+			result = append(result, port)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,202 @@
+package extractor
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/essentialkaos/bop/data"
+	"github.com/essentialkaos/bop/rpm"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// pythonDistRegex is regex for dist-info and egg-info paths
+var pythonDistRegex = regexp.MustCompile(`^/usr(?:/local)?/lib(?:64)?/python[0-9.]+/site-packages/[^/]+\.(dist|egg)-info$`)
+
+// pythonExtRegex is regex for compiled Python 3 extensions
+var pythonExtRegex = regexp.MustCompile(`/site-packages/.+\.cpython-[0-9]+[a-z]*-[^/]+\.so$`)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// addPythonDistsInfo extracts info about Python distributions from dist-info and
+// egg-info metadata
+func addPythonDistsInfo(info *data.Info, pkg *rpm.Package, payload *payloadReader) {
+	var dists []*data.PythonDist
+	var files []string
+
+	objects := make(map[string]bool)
+
+	for _, obj := range pkg.Payload {
+		objects[obj.Path] = true
+	}
+
+	for _, obj := range pkg.Payload {
+		match := pythonDistRegex.FindStringSubmatch(obj.Path)
+
+		if match == nil || obj.IsLink {
+			continue
+		}
+
+		dist := &data.PythonDist{Path: obj.Path, PkgVersion: getPythonVersion(pkg.Version)}
+
+		switch {
+		case !obj.IsDir:
+			// Old-style egg-info file contains metadata itself
+			dist.Metadata = obj.Path
+		case match[1] == "dist":
+			dist.Metadata = obj.Path + "/METADATA"
+		default:
+			dist.Metadata = obj.Path + "/PKG-INFO"
+		}
+
+		if !objects[dist.Metadata] {
+			continue
+		}
+
+		files = append(files, dist.Metadata)
+
+		if objects[obj.Path+"/entry_points.txt"] {
+			files = append(files, obj.Path+"/entry_points.txt")
+		}
+
+		dists = append(dists, dist)
+	}
+
+	payload.Collect(files, func(contents map[string][]byte) {
+		for _, dist := range dists {
+			dist.Name, dist.Version = parsePythonMetadata(contents[dist.Metadata])
+			dist.Scripts = parsePythonEntryPoints(contents[dist.Path+"/entry_points.txt"])
+
+			if dist.Name == "" {
+				continue
+			}
+
+			if dist.Version != "" && dist.Version != dist.PkgVersion {
+				info.Warnings = append(info.Warnings, fmt.Sprintf(
+					"Version of %s Python distribution (%s) doesn't match package version (%s)",
+					dist.Name, dist.Version, pkg.Version,
+				))
+			}
+
+			info.PythonDists = append(info.PythonDists, dist)
+		}
+	})
+}
+
+// checkPythonScripts removes console scripts from the list of apps (they are
+// checked with Python distributions) and adds warnings about missing scripts
+func checkPythonScripts(info *data.Info) {
+	apps := slices.Clone(info.Apps)
+	missing := make(map[string]bool)
+
+	for _, dist := range info.PythonDists {
+		var scripts []string
+
+		for _, script := range dist.Scripts {
+			if !slices.Contains(apps, script) {
+				if !missing[script] {
+					info.Warnings = append(info.Warnings, fmt.Sprintf(
+						"Console script %s from %s Python distribution is not packaged",
+						script, dist.Name,
+					))
+				}
+
+				missing[script] = true
+				continue
+			}
+
+			scripts = append(scripts, script)
+			info.Apps = slices.DeleteFunc(info.Apps, func(app string) bool {
+				return app == script
+			})
+		}
+
+		dist.Scripts = scripts
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// parsePythonMetadata parses distribution metadata and returns name and version
+func parsePythonMetadata(metadata []byte) (string, string) {
+	var name, version string
+
+	scanner := bufio.NewScanner(bytes.NewReader(metadata))
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		// Headers are followed by description
+		if line == "" {
+			break
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "Name":
+			name = strings.TrimSpace(value)
+		case "Version":
+			version = strings.TrimSpace(value)
+		}
+	}
+
+	return name, version
+}
+
+// parsePythonEntryPoints parses entry_points.txt and returns names of console
+// and GUI scripts
+func parsePythonEntryPoints(entryPoints []byte) []string {
+	var result []string
+	var section string
+
+	scanner := bufio.NewScanner(bytes.NewReader(entryPoints))
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "", strings.HasPrefix(line, "#"), strings.HasPrefix(line, ";"):
+			continue
+		case strings.HasPrefix(line, "["):
+			section = strings.Trim(line, "[] ")
+			continue
+		}
+
+		if section != "console_scripts" && section != "gui_scripts" {
+			continue
+		}
+
+		name, _, ok := strings.Cut(line, "=")
+
+		if ok && strings.TrimSpace(name) != "" {
+			result = append(result, strings.TrimSpace(name))
+		}
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+// getPythonVersion converts package version to Python version format
+// (e.g. 1.0~rc1 → 1.0rc1)
+func getPythonVersion(version string) string {
+	return strings.ReplaceAll(version, "~", "")
+}
@@ -0,0 +1,486 @@
+package extractor
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"path"
+	"strings"
+
+	"github.com/essentialkaos/bop/data"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// shellKeywords contains shell keywords which can precede command
+var shellKeywords = map[string]bool{
+	"if": true, "then": true, "else": true, "elif": true, "do": true,
+	"while": true, "until": true, "!": true, "{": true,
+	"exec": true, "command": true,
+}
+
+// userAddOptions contains useradd/adduser options with values
+var userAddOptions = map[string]string{
+	"-b": "", "--base-dir": "",
+	"-c": "", "--comment": "", "--gecos": "",
+	"-d": "home", "--home-dir": "home", "--home": "home",
+	"-e": "", "--expiredate": "",
+	"-f": "", "--inactive": "",
+	"-g": "gid", "--gid": "gid", "--ingroup": "gid",
+	"-G": "groups", "--groups": "groups",
+	"-k": "", "--skel": "",
+	"-K": "", "--key": "",
+	"-p": "", "--password": "",
+	"-P": "", "--prefix": "",
+	"-R": "", "--root": "",
+	"-s": "shell", "--shell": "shell",
+	"-u": "uid", "--uid": "uid",
+	"-Z": "", "--selinux-user": "",
+	"--firstuid": "", "--lastuid": "", "--conf": "",
+}
+
+// groupAddOptions contains groupadd/addgroup options with values
+var groupAddOptions = map[string]string{
+	"-g": "gid", "--gid": "gid",
+	"-K": "", "--key": "",
+	"-p": "", "--password": "",
+	"-P": "", "--prefix": "",
+	"-R": "", "--root": "",
+	"--firstgid": "", "--lastgid": "", "--conf": "",
+}
+
+// userModOptions contains usermod options with values
+var userModOptions = map[string]string{
+	"-c": "", "--comment": "",
+	"-d": "", "--home": "",
+	"-e": "", "--expiredate": "",
+	"-f": "", "--inactive": "",
+	"-g": "", "--gid": "",
+	"-G": "groups", "--groups": "groups",
+	"-l": "", "--login": "",
+	"-p": "", "--password": "",
+	"-P": "", "--prefix": "",
+	"-R": "", "--root": "",
+	"-s": "", "--shell": "",
+	"-u": "", "--uid": "",
+	"-Z": "", "--selinux-user": "",
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// extractAccountsData extracts info about users and groups created by scriptlet
+func extractAccountsData(script string, users data.UserMap, groups data.GroupMap) {
+	for _, cmd := range splitShellCommands(script) {
+		switch path.Base(cmd[0]) {
+		case "useradd", "adduser":
+			if parseAddUserToGroupCommand(cmd[1:], userAddOptions, users) {
+				continue
+			}
+
+			user, group := parseUserAddCommand(cmd[1:])
+
+			if group != nil {
+				mergeGroup(groups, group)
+			}
+
+			if user != nil {
+				mergeUser(users, user)
+			}
+
+		case "groupadd", "addgroup":
+			if parseAddUserToGroupCommand(cmd[1:], groupAddOptions, users) {
+				continue
+			}
+
+			group := parseGroupAddCommand(cmd[1:])
+
+			if group != nil {
+				mergeGroup(groups, group)
+			}
+
+		case "usermod":
+			parseUserModCommand(cmd[1:], users)
+
+		case "gpasswd":
+			// gpasswd -a user group
+			if len(cmd) == 4 && (cmd[1] == "-a" || cmd[1] == "--add") && isLiteral(cmd[3]) && users[cmd[2]] != nil {
+				addUserGroup(users[cmd[2]], cmd[3])
+			}
+		}
+	}
+}
+
+// parseUserAddCommand parses arguments of useradd or adduser command (Debian
+// "adduser --group" also creates group with the same name)
+func parseUserAddCommand(args []string) (*data.User, *data.Group) {
+	values, names := parseCommandOptions(args, userAddOptions)
+
+	if len(names) == 0 || !isLiteral(names[0]) {
+		return nil, nil
+	}
+
+	user := &data.User{
+		Name:  names[0],
+		Home:  getLiteral(values["home"]),
+		Shell: getLiteral(values["shell"]),
+	}
+
+	if isNumeric(values["uid"]) {
+		user.UID = values["uid"]
+	}
+
+	gid := values["gid"]
+
+	switch {
+	case isNumeric(gid):
+		user.GID = gid
+	case isLiteral(gid) && gid != "":
+		addUserGroup(user, gid)
+	}
+
+	for _, group := range strings.Split(values["groups"], ",") {
+		if isLiteral(group) && group != "" {
+			addUserGroup(user, group)
+		}
+	}
+
+	if values["--group"] == "true" {
+		return user, &data.Group{Name: user.Name}
+	}
+
+	return user, nil
+}
+
+// parseAddUserToGroupCommand parses Debian "adduser user group" and "addgroup
+// user group" commands, which only add existing user to existing group. Returns
+// true if command has such form.
+func parseAddUserToGroupCommand(args []string, options map[string]string, users data.UserMap) bool {
+	_, names := parseCommandOptions(args, options)
+
+	if len(names) != 2 {
+		return false
+	}
+
+	user := users[names[0]]
+
+	if user != nil && isLiteral(names[1]) {
+		addUserGroup(user, names[1])
+	}
+
+	return true
+}
+
+// parseGroupAddCommand parses arguments of groupadd or addgroup command
+func parseGroupAddCommand(args []string) *data.Group {
+	values, names := parseCommandOptions(args, groupAddOptions)
+
+	if len(names) != 1 || !isLiteral(names[0]) {
+		return nil
+	}
+
+	group := &data.Group{Name: names[0]}
+
+	if isNumeric(values["gid"]) {
+		group.GID = values["gid"]
+	}
+
+	return group
+}
+
+// parseUserModCommand parses arguments of usermod command and adds info about
+// supplementary groups of users created by package
+func parseUserModCommand(args []string, users data.UserMap) {
+	values, names := parseCommandOptions(args, userModOptions)
+
+	if len(names) != 1 || values["groups"] == "" {
+		return
+	}
+
+	user := users[names[0]]
+
+	if user == nil {
+		return
+	}
+
+	for _, group := range strings.Split(values["groups"], ",") {
+		if isLiteral(group) && group != "" {
+			addUserGroup(user, group)
+		}
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// splitShellCommands splits shell script into simple commands with respect to
+// quotes, escaping, line continuations and command separators
+func splitShellCommands(script string) [][]string {
+	var result [][]string
+	var cmd []string
+	var word strings.Builder
+	var quote rune
+	var hasWord, isComment, skipNext bool
+
+	flushWord := func() {
+		if !hasWord {
+			return
+		}
+
+		switch {
+		case skipNext:
+			// Redirection target
+			skipNext = false
+		case len(cmd) == 0 && shellKeywords[word.String()]:
+			// Keyword before command
+		case len(cmd) == 0 && isAssignment(word.String()):
+			// Environment variable before command
+		default:
+			cmd = append(cmd, word.String())
+		}
+
+		word.Reset()
+		hasWord = false
+	}
+
+	flushCommand := func() {
+		flushWord()
+		skipNext = false
+
+		if len(cmd) != 0 {
+			result = append(result, cmd)
+		}
+
+		cmd = nil
+	}
+
+	runes := []rune(script)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case isComment:
+			if r == '\n' {
+				isComment = false
+				flushCommand()
+			}
+
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+
+		case quote == '"':
+			switch {
+			case r == '"':
+				quote = 0
+			case r == '\\' && i+1 < len(runes) && strings.ContainsRune("\"\\$`\n", runes[i+1]):
+				i++
+				if runes[i] != '\n' {
+					word.WriteRune(runes[i])
+				}
+			default:
+				word.WriteRune(r)
+			}
+
+		case r == '\\':
+			if i+1 < len(runes) {
+				i++
+				if runes[i] != '\n' {
+					word.WriteRune(runes[i])
+					hasWord = true
+				}
+			}
+
+		case r == '\'' || r == '"':
+			quote, hasWord = r, true
+
+		case r == '#' && !hasWord:
+			isComment = true
+
+		case r == '(' && !hasWord:
+			// Subshell
+			flushCommand()
+
+		case r == ' ' || r == '\t':
+			flushWord()
+
+		case r == '\n' || r == ';' || r == '&' || r == '|' || r == ')':
+			flushCommand()
+
+		case r == '>' || r == '<':
+			// Descriptor number before redirection ("2>/dev/null")
+			if hasWord && isNumeric(word.String()) {
+				word.Reset()
+				hasWord = false
+			}
+
+			flushWord()
+
+			for i+1 < len(runes) && (runes[i+1] == '>' || runes[i+1] == '&') {
+				i++
+			}
+
+			// Descriptor duplication ("2>&1") has no target word
+			if i+1 < len(runes) && runes[i+1] >= '0' && runes[i+1] <= '9' && runes[i] == '&' {
+				i++
+				continue
+			}
+
+			skipNext = true
+
+		case r == '$' && i+1 < len(runes) && runes[i+1] == '(':
+			// Keep command substitution as a part of word
+			depth := 0
+
+			for ; i < len(runes); i++ {
+				word.WriteRune(runes[i])
+
+				if runes[i] == '(' {
+					depth++
+				} else if runes[i] == ')' {
+					depth--
+					if depth == 0 {
+						break
+					}
+				}
+			}
+
+			hasWord = true
+
+		default:
+			word.WriteRune(r)
+			hasWord = true
+		}
+	}
+
+	flushCommand()
+
+	return result
+}
+
+// parseCommandOptions parses command arguments and returns map with option
+// values and slice with positional arguments
+func parseCommandOptions(args []string, options map[string]string) (map[string]string, []string) {
+	values := make(map[string]string)
+
+	var names []string
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case arg == "--":
+			names = append(names, args[i+1:]...)
+			return values, names
+
+		case strings.HasPrefix(arg, "--"):
+			opt, value, hasValue := strings.Cut(arg, "=")
+			key, withValue := options[opt]
+
+			if !withValue {
+				values[opt] = "true"
+				continue
+			}
+
+			if !hasValue && i+1 < len(args) {
+				i++
+				value = args[i]
+			}
+
+			if key != "" {
+				values[key] = value
+			}
+
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			// Short options can be combined ("-rM") and can have attached
+			// value ("-u123")
+			for j := 1; j < len(arg); j++ {
+				key, withValue := options["-"+arg[j:j+1]]
+
+				if !withValue {
+					continue
+				}
+
+				value := arg[j+1:]
+
+				if value == "" && i+1 < len(args) {
+					i++
+					value = args[i]
+				}
+
+				if key != "" {
+					values[key] = value
+				}
+
+				break
+			}
+
+		default:
+			names = append(names, arg)
+		}
+	}
+
+	return values, names
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// getUser returns user from map or adds new one
+func getUser(users data.UserMap, name string) *data.User {
+	user := users[name]
+
+	if user == nil {
+		user = &data.User{Name: name}
+		users[name] = user
+	}
+
+	return user
+}
+
+// addUserGroup adds supplementary group to user info
+func addUserGroup(user *data.User, group string) {
+	switch {
+	case user.Group == "":
+		user.Group = group
+	case !strings.Contains(","+user.Group+",", ","+group+","):
+		user.Group += "," + group
+	}
+}
+
+// isLiteral returns true if value doesn't contain variables, command
+// substitutions or glob patterns
+func isLiteral(value string) bool {
+	return !strings.ContainsAny(value, "$`*?[")
+}
+
+// getLiteral returns value if it doesn't contain variables
+func getLiteral(value string) string {
+	if !isLiteral(value) {
+		return ""
+	}
+
+	return value
+}
+
+// isAssignment returns true if word is variable assignment
+func isAssignment(word string) bool {
+	name, _, ok := strings.Cut(word, "=")
+
+	if !ok || name == "" {
+		return false
+	}
+
+	for i, r := range name {
+		if r != '_' && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (i == 0 || r < '0' || r > '9') {
+			return false
+		}
+	}
+
+	return true
+}
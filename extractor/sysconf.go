@@ -0,0 +1,286 @@
+package extractor
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/essentialkaos/ek/v13/strutil"
+
+	"github.com/essentialkaos/bop/data"
+	"github.com/essentialkaos/bop/rpm"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var sysusersGlobs = []string{
+	"/usr/lib/sysusers.d/*.conf",
+}
+
+var tmpfilesGlobs = []string{
+	"/usr/lib/tmpfiles.d/*.conf",
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// addSysConfigsInfo extracts info about users, groups and runtime directories
+// from sysusers.d and tmpfiles.d configuration files
+func addSysConfigsInfo(info *data.Info, pkg *rpm.Package, payload *payloadReader) {
+	var sysusers, tmpfiles []string
+
+	for _, obj := range pkg.Payload {
+		switch {
+		case obj.IsDir || obj.IsLink:
+			continue
+		case matchAnyGlob(obj.Path, sysusersGlobs):
+			sysusers = append(sysusers, obj.Path)
+		case matchAnyGlob(obj.Path, tmpfilesGlobs):
+			tmpfiles = append(tmpfiles, obj.Path)
+		}
+	}
+
+	payload.Collect(append(sysusers, tmpfiles...), func(files map[string][]byte) {
+		sort.Strings(sysusers)
+		sort.Strings(tmpfiles)
+
+		for _, file := range sysusers {
+			for _, idRange := range parseSysusersConfig(files[file], info.Users, info.Groups) {
+				info.Warnings = append(info.Warnings, fmt.Sprintf(
+					"%s defines range %s for allocating IDs of users and groups, IDs allocated from this range are not checked",
+					file, idRange,
+				))
+			}
+		}
+
+		for _, file := range tmpfiles {
+			info.RuntimeDirs = append(info.RuntimeDirs, parseTmpfilesConfig(files[file])...)
+		}
+	})
+}
+
+// parseSysusersConfig parses sysusers.d configuration and returns ranges of IDs
+// defined in it
+func parseSysusersConfig(config []byte, users data.UserMap, groups data.GroupMap) []string {
+	var ranges []string
+
+	for _, fields := range readConfigRecords(config) {
+		if len(fields) < 2 {
+			continue
+		}
+
+		kind, name, id := fields[0], fields[1], getConfigField(fields, 2)
+
+		switch kind {
+		case "u", "u!":
+			user := &data.User{
+				Name:  name,
+				Home:  getConfigField(fields, 4),
+				Shell: getConfigField(fields, 5),
+			}
+
+			uid, gid, hasGroup := strings.Cut(id, ":")
+
+			if isNumeric(uid) {
+				user.UID = uid
+			}
+
+			switch {
+			case hasGroup && isNumeric(gid):
+				user.GID = gid
+			case hasGroup:
+				addUserGroup(user, gid)
+			default:
+				// User group with the same name is created automatically. It has
+				// the same ID only if such GID is not used, so GID can't be checked.
+				addUserGroup(user, name)
+				mergeGroup(groups, &data.Group{Name: name})
+			}
+
+			mergeUser(users, user)
+
+		case "g":
+			group := &data.Group{Name: name}
+
+			if isNumeric(id) {
+				group.GID = id
+			}
+
+			mergeGroup(groups, group)
+
+		case "m":
+			if id == "" {
+				continue
+			}
+
+			addUserGroup(getUser(users, name), id)
+
+		case "r":
+			if id != "" {
+				ranges = append(ranges, id)
+			}
+		}
+	}
+
+	return ranges
+}
+
+// parseTmpfilesConfig parses tmpfiles.d configuration and returns info about
+// directories
+func parseTmpfilesConfig(config []byte) []*rpm.Object {
+	var result []*rpm.Object
+
+	for _, fields := range readConfigRecords(config) {
+		if len(fields) < 2 {
+			continue
+		}
+
+		kind := strings.TrimRight(fields[0], "-=~^+")
+		path := fields[1]
+
+		switch {
+		case kind != "d" && kind != "D" && kind != "v" && kind != "q" && kind != "Q",
+			strings.Contains(path, "%"):
+			// Entries with "!" are created only on boot, entries with specifiers
+			// depend on environment
+			continue
+		}
+
+		dir := &rpm.Object{
+			Path:  path,
+			Mode:  0755,
+			User:  getConfigField(fields, 3),
+			Group: getConfigField(fields, 4),
+			IsDir: true,
+		}
+
+		mode, err := strconv.ParseUint(strings.TrimLeft(getConfigField(fields, 2), "~:"), 8, 32)
+
+		if err == nil {
+			dir.Mode = os.FileMode(mode)
+		}
+
+		if dir.User == "" {
+			dir.User = "root"
+		}
+
+		if dir.Group == "" {
+			dir.Group = "root"
+		}
+
+		result = append(result, dir)
+	}
+
+	return result
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// readConfigRecords reads records from sysusers.d/tmpfiles.d configuration
+func readConfigRecords(config []byte) [][]string {
+	var result [][]string
+
+	scanner := bufio.NewScanner(bytes.NewReader(config))
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		result = append(result, splitConfigLine(line))
+	}
+
+	return result
+}
+
+// splitConfigLine splits line into fields with respect to quotes
+func splitConfigLine(line string) []string {
+	var result []string
+	var field strings.Builder
+	var quote rune
+	var hasField bool
+
+	for _, r := range line {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote == 0 && (r == '"' || r == '\''):
+			quote, hasField = r, true
+		case quote == 0 && (r == ' ' || r == '\t'):
+			if hasField {
+				result = append(result, field.String())
+				field.Reset()
+				hasField = false
+			}
+		default:
+			field.WriteRune(r)
+			hasField = true
+		}
+	}
+
+	if hasField {
+		result = append(result, field.String())
+	}
+
+	return result
+}
+
+// getConfigField returns field with given index ("-" means empty value)
+func getConfigField(fields []string, index int) string {
+	if index >= len(fields) || fields[index] == "-" {
+		return ""
+	}
+
+	return fields[index]
+}
+
+// mergeUser adds user to map or fills empty fields of already added user
+func mergeUser(users data.UserMap, user *data.User) {
+	cur := users[user.Name]
+
+	if cur == nil {
+		users[user.Name] = user
+		return
+	}
+
+	cur.UID = strutil.Q(cur.UID, user.UID)
+	cur.GID = strutil.Q(cur.GID, user.GID)
+	cur.Home = strutil.Q(cur.Home, user.Home)
+	cur.Shell = strutil.Q(cur.Shell, user.Shell)
+
+	for _, group := range strings.Split(user.Group, ",") {
+		if group != "" {
+			addUserGroup(cur, group)
+		}
+	}
+}
+
+// mergeGroup adds group to map or fills empty fields of already added group
+func mergeGroup(groups data.GroupMap, group *data.Group) {
+	cur := groups[group.Name]
+
+	if cur == nil {
+		groups[group.Name] = group
+		return
+	}
+
+	cur.GID = strutil.Q(cur.GID, group.GID)
+}
+
+// isNumeric returns true if given string contains only digits
+func isNumeric(s string) bool {
+	_, err := strconv.ParseUint(s, 10, 32)
+	return err == nil
+}
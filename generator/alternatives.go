@@ -0,0 +1,57 @@
+package generator
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+
+	"github.com/essentialkaos/bop/data"
+	"github.com/essentialkaos/bop/distro"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// genAlternativesCheck generates checks for links managed by alternatives system
+func genAlternativesCheck(info *data.Info) string {
+	if len(info.Alternatives) == 0 {
+		return ""
+	}
+
+	data := `command "-" "Check alternatives"` + "\n"
+
+	for _, alt := range info.Alternatives {
+		data += fmt.Sprintf("  exist /etc/alternatives/%s\n", alt.Name)
+
+		for _, slave := range alt.Slaves {
+			data += fmt.Sprintf("  exist /etc/alternatives/%s\n", slave.Name)
+		}
+	}
+
+	data += "\n"
+
+	// SUSE provides only update-alternatives
+	cmd := "alternatives"
+
+	if info.Distro != nil && info.Distro.Family == distro.FAMILY_SUSE {
+		cmd = "update-alternatives"
+	}
+
+	for _, alt := range info.Alternatives {
+		data += fmt.Sprintf("command \"%s --display %s\" \"Check %s alternative\"\n", cmd, alt.Name, alt.Name)
+		data += "  exit 0\n"
+		data += fmt.Sprintf("  expect \"%s\"\n", alt.Path)
+
+		for _, slave := range alt.Slaves {
+			data += fmt.Sprintf("  expect \"%s\"\n", slave.Path)
+		}
+
+		data += "\n"
+	}
+
+	return data
+}
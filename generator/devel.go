@@ -0,0 +1,133 @@
+package generator
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	PATH "github.com/essentialkaos/ek/v13/path"
+
+	"github.com/essentialkaos/bop/data"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// buildTestSource is source of test program (escaped for shell)
+const buildTestSource = `int main\(void\) \{ return 0\; \}`
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// genBuildCheck generates checks for building and running test program with
+// packaged headers and libraries
+func genBuildCheck(name string, info *data.Info) string {
+	if !hasBuildCheck(info) {
+		return ""
+	}
+
+	var flags string
+
+	if len(info.PkgConfigs) != 0 {
+		flags = fmt.Sprintf("$(pkg-config --cflags --libs %s)", strings.Join(info.PkgConfigs, " "))
+	}
+
+	// Program is built in temporary directory. Headers which can't be included
+	// alone (e.g. C++ headers or headers which require other headers) are skipped.
+	cmd := "dir=$(mktemp -d) && flags=" + flags + " && "
+	cmd += "for h in " + strings.Join(getMainHeaders(info), " ") + "; do "
+	cmd += "echo | gcc -x c -fsyntax-only -include $h $flags - 2>/dev/null && set -- $@ -include $h; done; "
+	cmd += "echo " + buildTestSource + " | gcc -x c $@ -Wl,--no-as-needed"
+
+	// Reference to exported function makes linker check that library really
+	// provides it
+	symbol := getLinkSymbol(info)
+
+	if symbol != "" {
+		cmd += " -Wl,--require-defined=" + symbol
+	}
+
+	cmd += " -o $dir/test - $flags && $dir/test; rc=$?; rm -rf $dir; exit $rc"
+
+	data := fmt.Sprintf("command \"sh -c '%s'\" \"Build and run test program with %s headers and libraries\"\n", cmd, name)
+	data += "  exit 0\n"
+
+	return data + "\n"
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// hasBuildCheck returns true if build check can be generated for packages
+func hasBuildCheck(info *data.Info) bool {
+	return len(getMainHeaders(info)) != 0
+}
+
+// getBuildDeps returns packages required for build and pkg-config checks
+func getBuildDeps(info *data.Info) []string {
+	var result []string
+
+	if hasBuildCheck(info) {
+		result = append(result, "gcc")
+	}
+
+	if len(info.PCModules) != 0 || hasBuildCheck(info) && len(info.PkgConfigs) != 0 {
+		result = append(result, "pkgconfig")
+	}
+
+	return result
+}
+
+// getLinkSymbol returns name of function exported by one of libraries used
+// by pkg-config modules
+func getLinkSymbol(info *data.Info) string {
+	for _, name := range info.PkgConfigs {
+		pc := info.PCModules[name]
+
+		if pc == nil {
+			continue
+		}
+
+		for _, lib := range pc.Libs {
+			if !strings.HasPrefix(lib, "-l") {
+				continue
+			}
+
+			prefix := "lib" + strings.TrimPrefix(lib, "-l") + ".so"
+
+			for _, obj := range info.ELFs {
+				if obj.IsLib && obj.Symbol != "" && strings.HasPrefix(PATH.Base(obj.Path), prefix) {
+					return obj.Symbol
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// getMainHeaders returns headers which must be included into test program:
+// top-level headers and headers with the same name as directory or pkg-config
+// file (e.g. curl/curl.h)
+func getMainHeaders(info *data.Info) []string {
+	var result []string
+
+	for _, header := range info.HeaderFiles {
+		dir := PATH.Dir(header)
+		name := strings.TrimSuffix(PATH.Base(header), ".h")
+
+		switch {
+		case dir == ".",
+			dir == name,
+			slices.Contains(info.PkgConfigs, name),
+			slices.Contains(info.PkgConfigs, "lib"+name):
+			result = append(result, header)
+		}
+	}
+
+	return result
+}
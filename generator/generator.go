@@ -9,82 +9,211 @@ package generator
 
 import (
 	"fmt"
+	"regexp"
 	"slices"
 	"strings"
+	"time"
 
 	PATH "github.com/essentialkaos/ek/v13/path"
 
 	"github.com/essentialkaos/bop/data"
 	"github.com/essentialkaos/bop/rpm"
+	"github.com/essentialkaos/bop/systemd"
 )
 
 // ////////////////////////////////////////////////////////////////////////////////// //
 
+// Options contains generator options
+type Options struct {
+	Services      []string // List of services for checking
+	PinVersions   bool     // Use exact package versions in dependencies
+	DistSuffix    bool     // Always add OS version to recipe name
+	PythonImports bool     // Generate Python import tests
+
+	Ports data.PortMap // Ports used by services
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// libProvideRegex is regex for shared library in package provides
+// (e.g. libfoo.so.1()(64bit))
+var libProvideRegex = regexp.MustCompile(`^([^()]+\.so[^()]*)\(`)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
 // Generate generates bibop test data
-func Generate(name string, services []string, info *data.Info) (string, string) {
-	data := genHeader(name, info)
-	data += genDependencies(info)
+func Generate(name string, info *data.Info, options Options) (string, string) {
+	services := options.Services
+
+	data := genHeader(name, info, options.DistSuffix)
+	data += genDependencies(info, options.PinVersions)
 	data += genOptions(info)
 	data += genVariables(info, services)
+	data += genPythonVariables(info)
 	data += genEnvCheck(info)
-	data += genServicesCheck(info, services)
+	data += genServicesCheck(info, services, options.Ports)
+	data += genUnitsCheck(info, services)
 	data += genSharedLibsCheck(info)
+	data += genLinkingCheck(info)
 	data += genStaticLibsCheck(info)
 	data += genHeadersCheck(info)
 	data += genPkgConfigCheck(info)
+	data += genBuildCheck(name, info)
 	data += genPython2ModuleCheck(info)
 	data += genPython3ModuleCheck(info)
 	data += genPythonWheelsCheck(info)
+	data += genPythonDistsCheck(info)
 
-	return genOutputName(name, info), data
+	// python3-module checks use default interpreter, so modules for several or
+	// non-default versions of Python are checked with import tests for every
+	// interpreter
+	if options.PythonImports || isVersionedPython(info) {
+		data += genPythonImportsCheck(info)
+	}
+
+	data += genPerlModulesCheck(info)
+	data += genRubyGemsCheck(info)
+	data += genNodeModulesCheck(info)
+	data += genPHPExtensionsCheck(info)
+	data += genLuaModulesCheck(info)
+	data += genKernelModulesCheck(info)
+	data += genKernelConfigsCheck(info)
+	data += genDracutModulesCheck(info)
+	data += genAlternativesCheck(info)
+	data += genVirtualProvidesCheck(info)
+
+	return genOutputName(name, info, options.DistSuffix), data
 }
 
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 // genOutputName generates output file name
-func genOutputName(name string, info *data.Info) string {
-	osVersion := getOSVersion(info.Dist)
+func genOutputName(name string, info *data.Info, distSuffix bool) string {
+	suffix := info.Distro.Suffix()
 
-	if osVersion != -1 {
-		switch {
-		case len(info.Services) != 0,
-			len(info.Python2Modules) != 0,
-			len(info.Python3Modules) != 0:
-			return fmt.Sprintf("%s-c%d.recipe", name, osVersion)
-		}
+	if suffix == "" {
+		return fmt.Sprintf("%s.recipe", name)
+	}
+
+	switch {
+	case distSuffix,
+		info.Distro.IsKnown() && len(info.Services) != 0,
+		info.Distro.IsKnown() && len(info.Python2Modules) != 0,
+		info.Distro.IsKnown() && len(info.Python3Modules) != 0:
+		return fmt.Sprintf("%s-%s.recipe", name, suffix)
 	}
 
 	return fmt.Sprintf("%s.recipe", name)
 }
 
 // genHeader generates header
-func genHeader(name string, info *data.Info) string {
+func genHeader(name string, info *data.Info, distSuffix bool) string {
 	var data string
 
-	osVersion := getOSVersion(info.Dist)
-
-	if osVersion > 0 && len(info.Services) != 0 {
-		data += fmt.Sprintf("# Bibop recipe for %s for CentOS %d\n", name, osVersion)
+	if info.Distro.IsKnown() && (distSuffix || len(info.Services) != 0) {
+		data += fmt.Sprintf("# Bibop recipe for %s for %s\n", name, info.Distro)
 	} else {
 		data += fmt.Sprintf("# Bibop recipe for %s\n", name)
 	}
 
 	data += "# See more: https://kaos.sh/bibop\n\n"
-	data += "# Recipe generated by bop (https://kaos.sh/bop)\n\n"
+	data += "# Recipe generated by bop (https://kaos.sh/bop)\n"
+	data += genProvenance(info)
+	data += genUnsatisfiedDeps(info)
+	data += genBadRPaths(info)
+	data += "\n"
 
 	return data
 }
 
+// genProvenance generates info about packages used for recipe generation
+func genProvenance(info *data.Info) string {
+	var data string
+	var sources []string
+
+	for _, pkg := range info.Packages {
+		if pkg.SourceRPM != "" && !slices.Contains(sources, pkg.SourceRPM) {
+			sources = append(sources, pkg.SourceRPM)
+		}
+	}
+
+	for _, source := range sources {
+		data += fmt.Sprintf("# Source package: %s\n", source)
+	}
+
+	for _, pkg := range info.Packages {
+		data += fmt.Sprintf("# Package: %s", pkg.NEVRA())
+
+		if !pkg.BuildTime.IsZero() && pkg.BuildTime.Unix() != 0 {
+			data += fmt.Sprintf(" (built %s)", pkg.BuildTime.UTC().Format(time.DateOnly))
+		}
+
+		data += "\n"
+	}
+
+	for _, kernel := range getKernelVersions(info) {
+		data += fmt.Sprintf("# Kernel: %s\n", kernel)
+	}
+
+	return data
+}
+
+// genUnsatisfiedDeps generates info about requirements which are not satisfied
+// by packages from the set
+func genUnsatisfiedDeps(info *data.Info) string {
+	if len(info.UnsatisfiedDeps) == 0 {
+		return ""
+	}
+
+	data := "#\n# Unsatisfied dependencies:\n"
+
+	for _, dep := range info.UnsatisfiedDeps {
+		data += fmt.Sprintf("#   %s\n", dep)
+	}
+
+	return data
+}
+
+// genBadRPaths generates list of unexpected run-time search paths
+func genBadRPaths(info *data.Info) string {
+	var data string
+
+	for _, obj := range info.ELFs {
+		for _, rpath := range obj.BadPath {
+			data += fmt.Sprintf("#   %s: %s\n", obj.Path, rpath)
+		}
+	}
+
+	if data == "" {
+		return ""
+	}
+
+	return "#\n# Unexpected RPATH/RUNPATH:\n" + data
+}
+
 // genDependencies generates dependencies definition
-func genDependencies(info *data.Info) string {
-	return fmt.Sprintf("pkg %s\n\n", strings.Join(info.Pkgs, " "))
+func genDependencies(info *data.Info, pinVersions bool) string {
+	if !pinVersions || len(info.Packages) == 0 {
+		pkgs := append(slices.Clone(info.Pkgs), getExtraDeps(info)...)
+		return fmt.Sprintf("pkg %s\n\n", strings.Join(pkgs, " "))
+	}
+
+	var pkgs []string
+
+	for _, pkg := range info.Packages {
+		pkgs = append(pkgs, pkg.Name+"-"+pkg.EVR())
+	}
+
+	pkgs = append(pkgs, getExtraDeps(info)...)
+
+	return fmt.Sprintf("pkg %s\n\n", strings.Join(pkgs, " "))
 }
 
 // genOptions generates options
 func genOptions(info *data.Info) string {
 	var data string
 
-	if len(info.Services) == 0 {
+	if len(info.Services) == 0 && !hasExtraUnits(info) {
 		data += "fast-finish yes\n\n"
 	} else {
 		data += "require-root yes\n\n"
@@ -95,64 +224,62 @@ func genOptions(info *data.Info) string {
 
 // genVariables generates variables
 func genVariables(info *data.Info, services []string) string {
-	if getOSVersion(info.Dist) == 6 {
+	if !info.Distro.IsSystemd() {
 		return ""
 	}
 
-	if len(services) == 0 && len(info.Services) != 0 {
-		return "var delay 3\n\n"
+	var delay int
+
+	for _, service := range getCheckedServices(info, services) {
+		delay = max(delay, getServiceDelay(getServiceUnit(info, service)))
 	}
 
-	for _, service := range info.Services {
-		if slices.Contains(services, service) {
-			return "var delay 3\n\n"
-		}
+	if delay == 0 {
+		return ""
 	}
 
-	return ""
+	return fmt.Sprintf("var delay %d\n\n", delay)
 }
 
 // genEnvCheck generates environment checks
 func genEnvCheck(info *data.Info) string {
 	if isSimpleService(info) {
-		return genBasicEnvCheck(info)
+		return genBasicEnvCheck(info) + genRuntimeDirsCheck(info)
 	}
 
 	data := genAppsCheck(info)
 	data += genConfigsCheck(info)
 	data += genUsersAndGroupsCheck(info)
 	data += genServicesPresenceCheck(info)
+	data += genUnitsPresenceCheck(info)
+	data += genRuntimeDirsCheck(info)
 
 	return data
 }
 
 // genServicesCheck generates checks for services
-func genServicesCheck(info *data.Info, services []string) string {
+func genServicesCheck(info *data.Info, services []string, ports data.PortMap) string {
 	if len(info.Services) == 0 {
 		return ""
 	}
 
 	var data string
 
-	osVersion := getOSVersion(info.Dist)
+	isSystemd := info.Distro.IsSystemd()
 
-	for i := 0; i < 3; i++ {
-		for _, service := range info.Services {
-			if len(services) > 0 && !slices.Contains(services, service) {
-				continue
-			}
+	checked := getCheckedServices(info, services)
 
-			if strings.Contains(service, "@") {
-				continue
-			}
+	for i := 0; i < 3; i++ {
+		for _, service := range checked {
+			unit := getServiceUnit(info, service)
 
 			switch i {
 			case 0:
-				data += genServiceStartCheck(service, osVersion)
+				data += genServiceStartCheck(service, isSystemd, unit, getServicePorts(info, service, ports))
 			case 1:
-				data += genServiceStatusCheck(service, osVersion)
+				data += genServiceStatusCheck(service, isSystemd)
 			case 2:
-				data += genServiceStopCheck(service, osVersion)
+				data += genServiceStopCheck(service, isSystemd)
 			}
 
 			data += "\n"
@@ -174,9 +301,44 @@ func genSharedLibsCheck(info *data.Info) string {
 		data += fmt.Sprintf("  lib-loaded %s\n", lib)
 	}
 
+	for _, obj := range info.ELFs {
+		if obj.IsLib && obj.SONAME != "" {
+			data += fmt.Sprintf("  lib-soname %s %s\n", PATH.Base(obj.Path), obj.SONAME)
+		}
+	}
+
 	return data + "\n"
 }
 
+// genLinkingCheck generates checks for libraries required by binaries and
+// run-time search paths. Only linking with libraries from given packages is
+// checked, linking with system libraries (e.g. libc) doesn't need to be tested.
+func genLinkingCheck(info *data.Info) string {
+	var data string
+
+	libs := getProvidedLibs(info)
+
+	for _, obj := range info.ELFs {
+		if !obj.IsLib {
+			for _, lib := range obj.Needed {
+				if libs[lib] {
+					data += fmt.Sprintf("  lib-linked %s %s\n", obj.Path, lib)
+				}
+			}
+		}
+
+		for _, rpath := range obj.RPath {
+			data += fmt.Sprintf("  lib-rpath %s %s\n", obj.Path, rpath)
+		}
+	}
+
+	if data == "" {
+		return ""
+	}
+
+	return `command "-" "Check binaries linking"` + "\n" + data + "\n"
+}
+
 // genStaticLibsCheck generates checks for static libs
 func genStaticLibsCheck(info *data.Info) string {
 	if len(info.StaticLibs) == 0 {
@@ -187,7 +349,9 @@ func genStaticLibsCheck(info *data.Info) string {
 
 	for _, lib := range info.StaticLibs {
 		data += fmt.Sprintf("  exist %s\n", lib.Path)
-		data += fmt.Sprintf("  mode %s %o\n\n", lib.Path, lib.Mode)
+		data += genModeCheck(lib)
+		data += genChecksumCheck(lib)
+		data += "\n"
 	}
 
 	return data
@@ -220,7 +384,62 @@ func genPkgConfigCheck(info *data.Info) string {
 		data += fmt.Sprintf("  lib-config %s\n", cfg)
 	}
 
-	return data + "\n"
+	data += "\n"
+
+	for _, cfg := range info.PkgConfigs {
+		data += genPkgConfigModuleCheck(info.PCModules[cfg])
+	}
+
+	return data
+}
+
+// genPkgConfigModuleCheck generates checks for version, libraries and
+// dependencies of pkg-config module
+func genPkgConfigModuleCheck(pc *data.PkgConfig) string {
+	if pc == nil {
+		return ""
+	}
+
+	var data string
+
+	// Version of module may differ from package version, so we check
+	// version from pkg-config file
+	if pc.Version != "" {
+		data += fmt.Sprintf("command \"pkg-config --exact-version=%s %s\" \"Check version of %s pkg-config module\"\n", pc.Version, pc.Name, pc.Name)
+		data += "  exit 0\n"
+		data += "\n"
+	}
+
+	if len(pc.Libs) != 0 {
+		data += fmt.Sprintf("command \"pkg-config --libs %s\" \"Check libraries of %s pkg-config module\"\n", pc.Name, pc.Name)
+		data += "  exit 0\n"
+
+		for _, lib := range pc.Libs {
+			data += fmt.Sprintf("  expect \"%s\"\n", lib)
+		}
+
+		data += "\n"
+	}
+
+	if len(pc.Requires) != 0 {
+		data += fmt.Sprintf("command \"pkg-config --exists --print-errors %s\" \"Check dependencies of %s pkg-config module\"\n", pc.Name, pc.Name)
+		data += "  exit 0\n"
+		data += "\n"
+		data += fmt.Sprintf("command \"pkg-config --print-requires %s\" \"Check required modules of %s pkg-config module\"\n", pc.Name, pc.Name)
+		data += "  exit 0\n"
+
+		for _, req := range pc.Requires {
+			if req.Op == "" {
+				data += fmt.Sprintf("  expect \"%s\"\n", req.Name)
+			} else {
+				data += fmt.Sprintf("  expect \"%s %s %s\"\n", req.Name, req.Op, req.Version)
+			}
+		}
+
+		data += "\n"
+	}
+
+	return data
 }
 
 // genPython2ModuleCheck generates checks for Python 2 modules
@@ -233,19 +452,24 @@ func genPython2ModuleCheck(info *data.Info) string {
 
 	if len(info.Python2Dirs) > 0 {
 		for _, dir := range info.Python2Dirs {
-			data += fmt.Sprintf("  exist %s\n", getPythonModuleFilePath(dir.Path))
-			data += fmt.Sprintf("  dir %s\n\n", getPythonModuleFilePath(dir.Path))
+			data += fmt.Sprintf("  exist %s\n", getPythonModuleFilePath(dir.Path, false))
+			data += fmt.Sprintf("  dir %s\n\n", getPythonModuleFilePath(dir.Path, false))
 		}
 	}
 
 	if len(info.Python2Files) > 0 {
 		for _, file := range info.Python2Files {
-			data += fmt.Sprintf("  exist %s\n", getPythonModuleFilePath(file.Path))
+			data += fmt.Sprintf("  exist %s\n", getPythonModuleFilePath(file.Path, false))
 		}
 
 		data += "\n"
 	}
 
+	// Modules can't be imported if distribution doesn't provide interpreter
+	if !info.Distro.HasPython2() {
+		return data
+	}
+
 	for _, module := range info.Python2Modules {
 		data += fmt.Sprintf("  python-module %s\n", module)
 	}
@@ -259,23 +483,28 @@ func genPython3ModuleCheck(info *data.Info) string {
 		return ""
 	}
 
+	versioned := isVersionedPython(info)
 	data := `command "-" "Check Python 3 installation"` + "\n"
 
 	if len(info.Python3Dirs) > 0 {
 		for _, dir := range info.Python3Dirs {
-			data += fmt.Sprintf("  exist %s\n", getPythonModuleFilePath(dir.Path))
-			data += fmt.Sprintf("  dir %s\n\n", getPythonModuleFilePath(dir.Path))
+			data += fmt.Sprintf("  exist %s\n", getPythonModuleFilePath(dir.Path, versioned))
+			data += fmt.Sprintf("  dir %s\n\n", getPythonModuleFilePath(dir.Path, versioned))
 		}
 	}
 
 	if len(info.Python3Files) > 0 {
 		for _, file := range info.Python3Files {
-			data += fmt.Sprintf("  exist %s\n", getPythonModuleFilePath(file.Path))
+			data += fmt.Sprintf("  exist %s\n", getPythonModuleFilePath(file.Path, versioned))
 		}
 
 		data += "\n"
 	}
 
+	if versioned || !info.Distro.HasPython3() {
+		return data
+	}
+
 	for _, module := range info.Python3Modules {
 		data += fmt.Sprintf("  python3-module %s\n", module)
 	}
@@ -293,7 +522,24 @@ func genPythonWheelsCheck(info *data.Info) string {
 
 	for _, wheel := range info.PythonWheels {
 		data += fmt.Sprintf("  exist %s\n", wheel.Path)
-		data += fmt.Sprintf("  mode %s %o\n\n", wheel.Path, wheel.Mode)
+		data += genModeCheck(wheel)
+		data += genChecksumCheck(wheel)
+		data += "\n"
+	}
+
+	return data
+}
+
+// genVirtualProvidesCheck generates checks for virtual provides
+func genVirtualProvidesCheck(info *data.Info) string {
+	var data string
+
+	for _, provide := range info.VirtualProvides {
+		data += fmt.Sprintf(
+			"command \"rpm -q --whatprovides %s\" \"Check virtual provide %s\"\n",
+			provide, provide,
+		)
+		data += "  exit 0\n\n"
 	}
 
 	return data
@@ -320,6 +566,7 @@ func genBasicEnvCheck(info *data.Info) string {
 			data += fmt.Sprintf("  service-present %s\n", service)
 		}
 
+		data += genServicesEnvFilesCheck(info)
 		data += "\n"
 	}
 
@@ -387,14 +634,42 @@ func genServicesPresenceCheck(info *data.Info) string {
 		data += fmt.Sprintf("  service-present %s\n", service)
 	}
 
+	data += genServicesEnvFilesCheck(info)
+
 	return data + "\n"
 }
 
+// genServicesEnvFilesCheck generates checks for environment files used by services
+func genServicesEnvFilesCheck(info *data.Info) string {
+	var data string
+
+	checked := make(map[string]bool)
+
+	for _, service := range info.Services {
+		unit := info.Units[service+".service"]
+
+		if unit == nil || unit.Service == nil {
+			continue
+		}
+
+		for _, file := range unit.Service.EnvironmentFiles {
+			if file.IsOptional || checked[file.Path] || strings.Contains(file.Path, "%") {
+				continue
+			}
+
+			data += fmt.Sprintf("  exist %s\n", file.Path)
+			checked[file.Path] = true
+		}
+	}
+
+	return data
+}
+
 // genServiceStartCheck generates checks for service start
-func genServiceStartCheck(service string, osVersion int) string {
+func genServiceStartCheck(service string, isSystemd bool, unit *systemd.Unit, ports []*data.Port) string {
 	var data string
 
-	if osVersion < 7 {
+	if !isSystemd {
 		data = fmt.Sprintf("command \"service %s start\" \"Start %s daemon\"\n", service, service)
 		data += "  exit 0\n"
 	} else {
@@ -404,14 +679,72 @@ func genServiceStartCheck(service string, osVersion int) string {
 
 	data += fmt.Sprintf("  service-works %s\n", service)
 
+	var svc *systemd.Service
+
+	if isSystemd && unit != nil && unit.Service != nil {
+		svc = unit.Service.Instantiate(service + ".service")
+	}
+
+	if svc != nil && svc.PIDFile != "" && !strings.Contains(svc.PIDFile, "%") {
+		if svc.TimeoutStart > 0 {
+			data += fmt.Sprintf(
+				"  wait-pid %s %d\n", svc.PIDFile,
+				int(svc.TimeoutStart.Round(time.Second).Seconds()),
+			)
+		} else {
+			data += fmt.Sprintf("  wait-pid %s\n", svc.PIDFile)
+		}
+	}
+
+	for _, port := range ports {
+		data += genPortCheck(port)
+	}
+
+	if svc != nil && svc.User != "" && !strings.Contains(svc.User, "%") && svc.Type != systemd.TYPE_ONESHOT {
+		data += "\n" + genServiceOwnerCheck(service, svc)
+	}
+
+	return data
+}
+
+// genPortCheck generates check for port used by service
+func genPortCheck(port *data.Port) string {
+	if port.HTTPStatus == 0 {
+		return fmt.Sprintf("  connect tcp %s\n", port.Address())
+	}
+
+	host := port.Host
+
+	if host == "" {
+		host = "127.0.0.1"
+	}
+
+	return fmt.Sprintf("  http-status GET \"http://%s:%d\" %d\n", host, port.Port, port.HTTPStatus)
+}
+
+// genServiceOwnerCheck generates check for owner of service main process
+func genServiceOwnerCheck(service string, svc *systemd.Service) string {
+	// ps truncates names longer than 8 symbols if column width isn't set
+	data := fmt.Sprintf(
+		"command \"sh -c 'ps -o user:32=,group:32= -p $(systemctl show -p MainPID --value %s)'\" \"Check owner of %s process\"\n",
+		service, service,
+	)
+
+	data += "  exit 0\n"
+	data += fmt.Sprintf("  expect \"%s\"\n", svc.User)
+
+	if svc.Group != "" && svc.Group != svc.User {
+		data += fmt.Sprintf("  expect \"%s\"\n", svc.Group)
+	}
+
 	return data
 }
 
 // genServiceStatusCheck generates checks for service status check
-func genServiceStatusCheck(service string, osVersion int) string {
+func genServiceStatusCheck(service string, isSystemd bool) string {
 	var data string
 
-	if osVersion < 7 {
+	if !isSystemd {
 		data = fmt.Sprintf("command \"service %s status\" \"Check status of %s daemon\"\n", service, service)
 		data += "  exit 0\n"
 	} else {
@@ -423,21 +756,20 @@ func genServiceStatusCheck(service string, osVersion int) string {
 }
 
 // genServiceStopCheck generates checks for service stop
-func genServiceStopCheck(service string, osVersion int) string {
+func genServiceStopCheck(service string, isSystemd bool) string {
 	var data string
 
-	if osVersion < 7 {
-		data = fmt.Sprintf("command \"systemctl stop %s\" \"Stop %s daemon\"\n", service, service)
+	if !isSystemd {
+		data = fmt.Sprintf("command \"service %s stop\" \"Stop %s daemon\"\n", service, service)
 		data += "  exit 0\n"
 	} else {
-		data = fmt.Sprintf("command \"service %s stop\" \"Stop %s daemon\"\n", service, service)
+		data = fmt.Sprintf("command \"systemctl stop %s\" \"Stop %s daemon\"\n", service, service)
 		data += "  wait {delay}\n"
 	}
 
 	data += fmt.Sprintf("  !service-works %s\n", service)
 
 	return data
-
 }
 
 // genUsersAndGroupsCheck generates checks for users and groups
@@ -467,17 +799,37 @@ func genUsersAndGroupsCheck(info *data.Info) string {
 	return data
 }
 
+// genRuntimeDirsCheck generates checks for directories created by tmpfiles.d
+func genRuntimeDirsCheck(info *data.Info) string {
+	if len(info.RuntimeDirs) == 0 {
+		return ""
+	}
+
+	data := `command "-" "Check runtime directories"` + "\n"
+
+	for _, dir := range info.RuntimeDirs {
+		data += genConfigCheck(dir)
+	}
+
+	return data + "\n"
+}
+
 // genConfigCheck generates checks for configuration file or directory
 func genConfigCheck(config *rpm.Object) string {
 	data := fmt.Sprintf("  exist %s\n", config.Path)
 
 	if config.IsDir {
 		data = fmt.Sprintf("  dir %s\n", config.Path)
+	}
 
-		if config.Mode != 0755 {
-			data += fmt.Sprintf("  mode %s %o\n", config.Path, config.Mode)
-		}
-	} else if config.Mode != 0644 {
+	// Mode and owner of objects read from repository metadata are unknown
+	if config.NoMeta {
+		return data
+	}
+
+	switch {
+	case config.IsDir && config.Mode != 0755,
+		!config.IsDir && config.Mode != 0644:
 		data += fmt.Sprintf("  mode %s %o\n", config.Path, config.Mode)
 	}
 
@@ -491,6 +843,25 @@ func genConfigCheck(config *rpm.Object) string {
 	return data
 }
 
+// genModeCheck generates mode check for given object if its mode is known
+func genModeCheck(obj *rpm.Object) string {
+	if obj.NoMeta {
+		return ""
+	}
+
+	return fmt.Sprintf("  mode %s %o\n", obj.Path, obj.Mode)
+}
+
+// genChecksumCheck generates checksum check for given object if package
+// contains SHA-256 digest for it
+func genChecksumCheck(obj *rpm.Object) string {
+	if obj.NoMeta || len(obj.Digest) != 64 {
+		return ""
+	}
+
+	return fmt.Sprintf("  checksum %s %s\n", obj.Path, obj.Digest)
+}
+
 // genUserCheck generates checks for given user
 func genUserCheck(user *data.User) string {
 	data := fmt.Sprintf("  user-exist %s\n", user.Name)
@@ -504,7 +875,9 @@ func genUserCheck(user *data.User) string {
 	}
 
 	if user.Group != "" {
-		data += fmt.Sprintf("  user-group %s %s\n", user.Name, user.Group)
+		for _, group := range strings.Split(user.Group, ",") {
+			data += fmt.Sprintf("  user-group %s %s\n", user.Name, group)
+		}
 	}
 
 	if user.Home != "" {
@@ -529,24 +902,128 @@ func genGroupCheck(group *data.Group) string {
 	return data
 }
 
-// getOSVersion returns OS version number
-func getOSVersion(dist string) int {
-	switch {
-	case strings.HasPrefix(dist, "el6"):
-		return 6
-	case strings.HasPrefix(dist, "el7"):
-		return 7
-	case strings.HasPrefix(dist, "el8"):
-		return 8
+// getCheckedServices returns names of services and instances of templated
+// services which must be checked
+func getCheckedServices(info *data.Info, services []string) []string {
+	var result []string
+
+	for _, service := range info.Services {
+		if isTriggeredService(info, service) {
+			continue
+		}
+
+		if !strings.HasSuffix(service, "@") {
+			if len(services) == 0 || slices.Contains(services, service) {
+				result = append(result, service)
+			}
+
+			continue
+		}
+
+		var instances []string
+
+		for _, s := range services {
+			if strings.HasPrefix(s, service) && len(s) > len(service) {
+				instances = append(instances, s)
+			}
+		}
+
+		unit := info.Units[service+".service"]
+
+		if len(instances) == 0 && len(services) == 0 && unit != nil && unit.DefaultInstance != "" {
+			instances = append(instances, service+unit.DefaultInstance)
+		}
+
+		result = append(result, instances...)
 	}
 
-	return -1
+	return result
+}
+
+// getServicePorts returns ports used by service or instance of templated service
+func getServicePorts(info *data.Info, service string, ports data.PortMap) []*data.Port {
+	result := make(data.PortMap)
+
+	// Ports found in package are stored for template (e.g. foo@), while user can
+	// define ports for template or particular instance (e.g. foo@main)
+	template := strings.TrimSuffix(systemd.TemplateName(service+".service"), ".service")
+	names := []string{template}
+
+	if template != service {
+		names = append(names, service)
+	}
+
+	for _, name := range names {
+		for _, port := range info.Ports[name] {
+			result.Add(service, port)
+		}
+	}
+
+	for _, name := range names {
+		for _, port := range ports[name] {
+			result.Add(service, port)
+		}
+	}
+
+	return result[service]
+}
+
+// getServiceUnit returns unit info for service or instance of templated service
+func getServiceUnit(info *data.Info, service string) *systemd.Unit {
+	return info.Units[systemd.TemplateName(service+".service")]
+}
+
+// getServiceDelay returns delay in seconds required for service start
+func getServiceDelay(unit *systemd.Unit) int {
+	// systemctl waits until service with notification support is ready
+	if unit != nil && unit.Service != nil && unit.Service.IsNotifying() {
+		return 1
+	}
+
+	return 3
+}
+
+// getProvidedLibs returns SONAMEs of shared libraries provided by packages
+func getProvidedLibs(info *data.Info) map[string]bool {
+	result := make(map[string]bool)
+
+	for _, obj := range info.ELFs {
+		if obj.IsLib && obj.SONAME != "" {
+			result[obj.SONAME] = true
+		}
+	}
+
+	for _, pkg := range info.Packages {
+		for _, dep := range pkg.Deps.Provides {
+			match := libProvideRegex.FindStringSubmatch(dep.Name)
+
+			if match != nil {
+				result[match[1]] = true
+			}
+		}
+	}
+
+	return result
+}
+
+// getExtraDeps returns packages required for checks
+func getExtraDeps(info *data.Info) []string {
+	result := append(getBuildDeps(info), getModulesDeps(info)...)
+	return append(result, getKernelDeps(info)...)
 }
 
 // getPythonModuleFilePath replaces part of path to variable
-func getPythonModuleFilePath(path string) string {
+func getPythonModuleFilePath(path string, versioned bool) string {
 	pathDir := PATH.DirN(path, 4)
 
+	if versioned {
+		siteDir := python3SiteDirRegex.FindString(path)
+
+		if siteDir != "" {
+			return "{" + getPython3SiteDirVariable(siteDir) + "}" + strings.TrimPrefix(path, siteDir)
+		}
+	}
+
 	switch {
 	case strings.HasPrefix(pathDir, "/usr/lib/python2"):
 		path = strings.ReplaceAll(path, pathDir, "{PYTHON2_SITELIB}")
@@ -575,6 +1052,7 @@ func isSimpleService(info *data.Info) bool {
 	case len(info.Apps) > 3,
 		len(info.Configs) > 3,
 		len(info.Services) > 1,
+		hasExtraUnits(info),
 		len(info.Users) > 1,
 		len(info.Groups) > 1:
 		return false
@@ -0,0 +1,357 @@
+package generator
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/essentialkaos/bop/data"
+	"github.com/essentialkaos/bop/extractor"
+	"github.com/essentialkaos/bop/rpm"
+
+	. "github.com/essentialkaos/check"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func Test(t *testing.T) { TestingT(t) }
+
+type GeneratorSuite struct{}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var _ = Suite(&GeneratorSuite{})
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (s *GeneratorSuite) TestGenerate(c *C) {
+	tests := []struct {
+		Name     string
+		Dist     string
+		Payload  []*rpm.Object
+		Contents map[string]string
+		Options  Options
+		Contains []string
+		Excludes []string
+	}{
+		{
+			Name:    "apps",
+			Payload: []*rpm.Object{genTestObject("/usr/bin/app", 0755)},
+			Contains: []string{
+				`command "-" "Check environment"`,
+				"  app app",
+			},
+		},
+		{
+			Name: "pkg-config modules",
+			Payload: []*rpm.Object{
+				genTestObject("/usr/include/foo.h", 0644),
+				genTestObject("/usr/lib64/pkgconfig/foo.pc", 0644),
+			},
+			Contents: map[string]string{
+				"/usr/lib64/pkgconfig/foo.pc": "Name: foo\nVersion: 1.0.1\nLibs: -lfoo\n",
+			},
+			Contains: []string{
+				`command "pkg-config --exact-version=1.0.1 foo" "Check version of foo pkg-config module"`,
+				`command "pkg-config --libs foo" "Check libraries of foo pkg-config module"`,
+				`  expect "-lfoo"`,
+			},
+			Excludes: []string{"/tmp/bop-", "--require-defined", "--modversion", `expect "1.0"`},
+		},
+		{
+			Name: "service with user",
+			Payload: []*rpm.Object{
+				genTestObject("/usr/bin/app", 0755),
+				genTestObject("/usr/lib/systemd/system/app.service", 0644),
+			},
+			Contents: map[string]string{
+				"/usr/lib/systemd/system/app.service": "[Service]\nUser=app\nGroup=app\nExecStart=/usr/bin/app\n",
+			},
+			Options: Options{Services: []string{"app"}},
+			Contains: []string{
+				"  service-present app",
+				`command "systemctl start app" "Start app daemon"`,
+				"  service-works app",
+				`command "sh -c 'ps -o user:32=,group:32= -p $(systemctl show -p MainPID --value app)'" "Check owner of app process"`,
+			},
+		},
+		{
+			Name: "socket unit",
+			Payload: []*rpm.Object{
+				genTestObject("/usr/lib/systemd/system/app.service", 0644),
+				genTestObject("/usr/lib/systemd/system/app.socket", 0644),
+			},
+			Contents: map[string]string{
+				"/usr/lib/systemd/system/app.service": "[Service]\nExecStart=/usr/bin/app\n",
+				"/usr/lib/systemd/system/app.socket":  "[Socket]\nListenStream=0.0.0.0:6379\nListenStream=/run/app.sock\n",
+			},
+			Contains: []string{
+				"  connect tcp :6379",
+				"  wait-fs /run/app.sock",
+				`command "systemctl stop app.socket app.service" "Stop app socket"`,
+			},
+		},
+		{
+			Name: "kernel and dracut modules",
+			Payload: []*rpm.Object{
+				genTestObject("/lib/modules/5.14.0-1.el9.x86_64/extra/foo/foo.ko.xz", 0644),
+				genTestDir("/usr/lib/dracut/modules.d/90foo"),
+			},
+			Contains: []string{
+				"pkg app kmod dracut",
+				`command "modinfo -k 5.14.0-1.el9.x86_64 -F vermagic foo" "Check foo kernel module info"`,
+				`  expect "5.14.0-1.el9.x86_64"`,
+			},
+		},
+		{
+			Name: "objects with unknown metadata",
+			Payload: []*rpm.Object{
+				{Path: "/usr/lib64/libfoo.a", NoMeta: true},
+			},
+			Contains: []string{"  exist /usr/lib64/libfoo.a"},
+			Excludes: []string{"mode /usr/lib64/libfoo.a", "checksum"},
+		},
+		{
+			Name: "Perl modules",
+			Payload: []*rpm.Object{
+				genTestObject("/usr/share/perl5/vendor_perl/Foo/Bar.pm", 0644),
+				genTestObject("/usr/share/perl5/vendor_perl/Foo/Bar/Baz.pm", 0644),
+			},
+			Contains: []string{
+				"pkg app perl",
+				`command "perl -MFoo::Bar -e 1" "Load Foo::Bar Perl module"`,
+			},
+			Excludes: []string{"Foo::Bar::Baz"},
+		},
+		{
+			Name: "Ruby gems and Node.js modules",
+			Payload: []*rpm.Object{
+				genTestObject("/usr/share/gems/specifications/foo-1.0.gemspec", 0644),
+				genTestObject("/usr/lib/node_modules/left-pad/package.json", 0644),
+			},
+			Contents: map[string]string{
+				"/usr/lib/node_modules/left-pad/package.json": `{"name": "left-pad", "version": "1.3.0"}`,
+			},
+			Contains: []string{
+				"pkg app ruby rubygems nodejs",
+				`command "ruby -e 'gem %q(foo)'" "Activate foo Ruby gem"`,
+				`command "node -p 'require(process.argv[1]).version' /usr/lib/node_modules/left-pad/package.json" "Check left-pad Node.js module version"`,
+			},
+		},
+		{
+			Name: "Python modules",
+			Payload: []*rpm.Object{
+				genTestDir("/usr/lib/python2.7/site-packages/foo"),
+				genTestDir("/usr/lib/python3.9/site-packages/foo"),
+			},
+			Contains: []string{"  python3-module foo", `command "-" "Check Python 2 installation"`},
+			Excludes: []string{"  python-module foo"},
+		},
+		{
+			Name: "Python 2 modules on distribution with python2 in additional repositories",
+			Dist: "el8",
+			Payload: []*rpm.Object{
+				genTestDir("/usr/lib/python2.7/site-packages/foo"),
+			},
+			Contains: []string{"  python-module foo"},
+		},
+		{
+			Name: "Python modules for several versions of Python 3",
+			Payload: []*rpm.Object{
+				genTestDir("/usr/lib/python3.9/site-packages/foo"),
+				genTestDir("/usr/lib/python3.11/site-packages/foo"),
+			},
+			Contains: []string{
+				"  exist {python3_9_sitelib}/foo",
+				`command "python3.9 -c '` + pythonImportCode + `' foo" "Import modules with Python 3.9"`,
+				`command "python3.11 -c '` + pythonImportCode + `' foo" "Import modules with Python 3.11"`,
+			},
+			Excludes: []string{"  python3-module foo"},
+		},
+		{
+			Name: "Python modules for non-default version of Python 3",
+			Dist: "el9",
+			Payload: []*rpm.Object{
+				genTestDir("/usr/lib/python3.11/site-packages/foo"),
+			},
+			Contains: []string{
+				"var python3_11_sitelib /usr/lib/python3.11/site-packages",
+				"  exist {python3_11_sitelib}/foo",
+				`command "python3.11 -c '` + pythonImportCode + `' foo" "Import modules with Python 3.11"`,
+			},
+			Excludes: []string{"  python3-module foo", "{PYTHON3_SITELIB}"},
+		},
+		{
+			Name: "Python modules for default version of Python 3",
+			Dist: "el9",
+			Payload: []*rpm.Object{
+				genTestDir("/usr/lib/python3.9/site-packages/foo"),
+			},
+			Contains: []string{"  python3-module foo", "  exist {PYTHON3_SITELIB}/foo"},
+			Excludes: []string{"python3_9_sitelib", "python3.9 -c"},
+		},
+		{
+			Name: "Python modules on distribution without Python 3",
+			Dist: "el6",
+			Payload: []*rpm.Object{
+				genTestDir("/usr/lib/python2.6/site-packages/foo"),
+				genTestDir("/usr/lib/python3.4/site-packages/foo"),
+			},
+			Contains: []string{"  python-module foo", `command "-" "Check Python 3 installation"`},
+			Excludes: []string{"  python3-module foo", "python3 -c"},
+		},
+	}
+
+	for _, test := range tests {
+		c.Log(test.Name)
+
+		recipe := genTestRecipe(c, test.Dist, test.Payload, test.Contents, test.Options)
+
+		for _, line := range test.Contains {
+			c.Assert(strings.Contains(recipe, line+"\n"), Equals, true, Commentf("%q not found in:\n%s", line, recipe))
+		}
+
+		for _, line := range test.Excludes {
+			c.Assert(strings.Contains(recipe, line), Equals, false, Commentf("%q found in:\n%s", line, recipe))
+		}
+	}
+}
+
+func (s *GeneratorSuite) TestServiceCommands(c *C) {
+	c.Assert(genServiceStatusCheck("app", true), Equals,
+		"command \"systemctl status app\" \"Check status of app daemon\"\n  expect \"active (running)\"\n")
+	c.Assert(genServiceStatusCheck("app", false), Equals,
+		"command \"service app status\" \"Check status of app daemon\"\n  exit 0\n")
+
+	c.Assert(genServiceStopCheck("app", true), Equals,
+		"command \"systemctl stop app\" \"Stop app daemon\"\n  wait {delay}\n  !service-works app\n")
+	c.Assert(genServiceStopCheck("app", false), Equals,
+		"command \"service app stop\" \"Stop app daemon\"\n  exit 0\n  !service-works app\n")
+}
+
+func (s *GeneratorSuite) TestListenCheck(c *C) {
+	c.Assert(genListenCheck("80"), Equals, "  connect tcp :80\n")
+	c.Assert(genListenCheck("[::]:443"), Equals, "  connect tcp :443\n")
+	c.Assert(genListenCheck("127.0.0.1:6379"), Equals, "  connect tcp 127.0.0.1:6379\n")
+	c.Assert(genListenCheck("/run/app.sock"), Equals, "  wait-fs /run/app.sock\n")
+	c.Assert(genListenCheck("/run/%i.sock"), Equals, "")
+	c.Assert(genListenCheck("@app"), Equals, "")
+	c.Assert(genListenCheck("app.example.com:80"), Equals, "")
+}
+
+func (s *GeneratorSuite) TestServicePorts(c *C) {
+	info := &data.Info{Ports: data.PortMap{
+		"app@": {{Port: 6379}, {Port: 8080}},
+	}}
+
+	hints := data.PortMap{
+		"app@main": {{Port: 8080, HTTPStatus: 401}},
+	}
+
+	ports := getServicePorts(info, "app@main", hints)
+
+	c.Assert(ports, DeepEquals, []*data.Port{{Port: 6379}, {Port: 8080, HTTPStatus: 401}})
+	c.Assert(info.Ports["app@"][1].HTTPStatus, Equals, 0)
+
+	c.Assert(genPortCheck(ports[0]), Equals, "  connect tcp :6379\n")
+	c.Assert(genPortCheck(ports[1]), Equals, "  http-status GET \"http://127.0.0.1:8080\" 401\n")
+}
+
+func (s *GeneratorSuite) TestLinkingCheck(c *C) {
+	info := &data.Info{
+		Packages: []*rpm.Package{{
+			Name: "libfoo",
+			Deps: rpm.Dependencies{Provides: []*rpm.Dependency{{Name: "libfoo.so.1()(64bit)"}}},
+		}},
+		ELFs: []*data.ELF{
+			{Path: "/usr/lib64/libbar.so.2.0", SONAME: "libbar.so.2", IsLib: true},
+			{
+				Path:    "/usr/bin/app",
+				Needed:  []string{"libfoo.so.1", "libbar.so.2", "libc.so.6"},
+				RPath:   []string{"$ORIGIN/../lib64/app"},
+				BadPath: []string{"/usr/lib64"},
+			},
+		},
+	}
+
+	c.Assert(genLinkingCheck(info), Equals, `command "-" "Check binaries linking"`+"\n"+
+		"  lib-linked /usr/bin/app libfoo.so.1\n"+
+		"  lib-linked /usr/bin/app libbar.so.2\n"+
+		"  lib-rpath /usr/bin/app $ORIGIN/../lib64/app\n\n",
+	)
+
+	c.Assert(genBadRPaths(info), Equals, "#\n# Unexpected RPATH/RUNPATH:\n#   /usr/bin/app: /usr/lib64\n")
+}
+
+func (s *GeneratorSuite) TestBuildCheck(c *C) {
+	info := &data.Info{
+		HeaderFiles: []string{"foo.h", "foo/bar.h"},
+		PkgConfigs:  []string{"foo"},
+		PCModules:   map[string]*data.PkgConfig{"foo": {Name: "foo", Libs: []string{"-L/usr/lib64", "-lfoo"}}},
+		ELFs: []*data.ELF{
+			{Path: "/usr/lib64/libfoobar.so.1.0", SONAME: "libfoobar.so.1", Symbol: "foobar_init", IsLib: true},
+			{Path: "/usr/lib64/libfoo.so.1.0", SONAME: "libfoo.so.1", Symbol: "foo_init", IsLib: true},
+		},
+	}
+
+	c.Assert(getLinkSymbol(info), Equals, "foo_init")
+
+	check := genBuildCheck("foo", info)
+
+	c.Assert(strings.Contains(check, "dir=$(mktemp -d)"), Equals, true)
+	c.Assert(strings.Contains(check, "for h in foo.h; do"), Equals, true)
+	c.Assert(strings.Contains(check, "-Wl,--require-defined=foo_init"), Equals, true)
+	c.Assert(strings.Contains(check, "rm -rf $dir"), Equals, true)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// genTestRecipe generates recipe for package with given payload
+func genTestRecipe(c *C, dist string, payload []*rpm.Object, contents map[string]string, options Options) string {
+	files := make(map[string][]byte)
+
+	if dist == "" {
+		dist = "el9"
+	}
+
+	for path, content := range contents {
+		files[path] = []byte(content)
+	}
+
+	pkg := &rpm.Package{
+		Name:    "app",
+		Version: "1.0",
+		Release: "1." + dist,
+		Arch:    "x86_64",
+		Dist:    dist,
+		Payload: payload,
+	}
+
+	rpm.SetReaders(rpm.NewMemoryReader().Add("app.rpm", pkg).AddContent("app.rpm", files))
+
+	infos, err := extractor.ProcessPackagesByDist([]string{"app.rpm"})
+
+	c.Assert(err, IsNil)
+	c.Assert(infos, HasLen, 1)
+
+	_, recipe := Generate("app", infos[0], options)
+
+	return recipe
+}
+
+// genTestObject generates payload object for file with given mode
+func genTestObject(path string, mode os.FileMode) *rpm.Object {
+	return &rpm.Object{Path: path, User: "root", Group: "root", Mode: mode}
+}
+
+// genTestDir generates payload object for directory
+func genTestDir(path string) *rpm.Object {
+	return &rpm.Object{Path: path, User: "root", Group: "root", Mode: 0755, IsDir: true}
+}
@@ -0,0 +1,139 @@
+package generator
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+
+	PATH "github.com/essentialkaos/ek/v13/path"
+
+	"github.com/essentialkaos/bop/data"
+	"github.com/essentialkaos/bop/rpm"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// dracutModuleRegex is regex for dracut module directory name (e.g. 90kernel-modules)
+var dracutModuleRegex = regexp.MustCompile(`^[0-9]{2}(.+)$`)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// genKernelModulesCheck generates checks for kernel modules
+func genKernelModulesCheck(info *data.Info) string {
+	if len(info.KernelModules) == 0 {
+		return ""
+	}
+
+	data := `command "-" "Check kernel modules"` + "\n"
+
+	for _, module := range info.KernelModules {
+		data += genConfigCheck(module.File)
+	}
+
+	data += "\n"
+
+	// Module is searched in modules index of kernel which it is built for, so
+	// check works even if another kernel is running
+	for _, module := range info.KernelModules {
+		data += fmt.Sprintf(
+			"command \"modinfo -k %s -F vermagic %s\" \"Check %s kernel module info\"\n",
+			module.KernelVersion, module.Name, module.Name,
+		)
+		data += "  exit 0\n"
+		data += fmt.Sprintf("  expect \"%s\"\n", module.KernelVersion)
+		data += "\n"
+	}
+
+	return data
+}
+
+// genKernelConfigsCheck generates checks for udev rules and modprobe configuration
+func genKernelConfigsCheck(info *data.Info) string {
+	var data string
+
+	data += genFilesCheck("Check udev rules", info.UdevRules)
+	data += genFilesCheck("Check modprobe configuration", info.ModprobeConfs)
+
+	return data
+}
+
+// genDracutModulesCheck generates checks for dracut modules
+func genDracutModulesCheck(info *data.Info) string {
+	if len(info.DracutModules) == 0 {
+		return ""
+	}
+
+	data := `command "-" "Check dracut modules"` + "\n"
+
+	for _, dir := range info.DracutModules {
+		data += fmt.Sprintf("  dir %s\n", dir.Path)
+		data += fmt.Sprintf("  exist %s/module-setup.sh\n", dir.Path)
+	}
+
+	data += "\n"
+	data += `command "dracut --list-modules" "Check dracut modules list"` + "\n"
+	data += "  exit 0\n"
+
+	for _, dir := range info.DracutModules {
+		match := dracutModuleRegex.FindStringSubmatch(PATH.Base(dir.Path))
+
+		if match != nil {
+			data += fmt.Sprintf("  expect \"%s\"\n", match[1])
+		}
+	}
+
+	return data + "\n"
+}
+
+// genFilesCheck generates existence and mode checks for given files
+func genFilesCheck(desc string, files []*rpm.Object) string {
+	if len(files) == 0 {
+		return ""
+	}
+
+	data := fmt.Sprintf("command \"-\" \"%s\"\n", desc)
+
+	for _, file := range files {
+		data += genConfigCheck(file)
+	}
+
+	return data + "\n"
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// getKernelDeps returns packages required for kernel modules and dracut
+// modules checks
+func getKernelDeps(info *data.Info) []string {
+	var result []string
+
+	if len(info.KernelModules) != 0 {
+		result = append(result, "kmod")
+	}
+
+	if len(info.DracutModules) != 0 {
+		result = append(result, "dracut")
+	}
+
+	return result
+}
+
+// getKernelVersions returns versions of kernels targeted by kernel modules
+func getKernelVersions(info *data.Info) []string {
+	var result []string
+
+	for _, module := range info.KernelModules {
+		if !slices.Contains(result, module.KernelVersion) {
+			result = append(result, module.KernelVersion)
+		}
+	}
+
+	return result
+}
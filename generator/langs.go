@@ -0,0 +1,134 @@
+package generator
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+
+	"github.com/essentialkaos/bop/data"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// genPerlModulesCheck generates checks for loading Perl modules
+func genPerlModulesCheck(info *data.Info) string {
+	var data string
+
+	for _, module := range info.PerlModules {
+		data += fmt.Sprintf("command \"perl -M%s -e 1\" \"Load %s Perl module\"\n", module.Name, module.Name)
+		data += "  exit 0\n"
+		data += "\n"
+	}
+
+	return data
+}
+
+// genRubyGemsCheck generates checks for Ruby gems
+func genRubyGemsCheck(info *data.Info) string {
+	var data string
+
+	for _, gem := range info.RubyGems {
+		if gem.Version != "" {
+			data += fmt.Sprintf("command \"gem list -i -e %s -v %s\" \"Check %s Ruby gem version\"\n", gem.Name, gem.Version, gem.Name)
+			data += "  exit 0\n"
+			data += "\n"
+		}
+
+		data += fmt.Sprintf("command \"ruby -e 'gem %%q(%s)'\" \"Activate %s Ruby gem\"\n", gem.Name, gem.Name)
+		data += "  exit 0\n"
+		data += "\n"
+	}
+
+	return data
+}
+
+// genNodeModulesCheck generates checks for Node.js modules
+func genNodeModulesCheck(info *data.Info) string {
+	var data string
+
+	for _, module := range info.NodeModules {
+		if module.Version != "" {
+			data += fmt.Sprintf(
+				"command \"node -p 'require(process.argv[1]).version' %s/package.json\" \"Check %s Node.js module version\"\n",
+				module.Path, module.Name,
+			)
+			data += "  exit 0\n"
+			data += fmt.Sprintf("  expect \"%s\"\n", module.Version)
+			data += "\n"
+		}
+
+		if module.Loadable {
+			data += fmt.Sprintf(
+				"command \"node -e 'require(process.argv[1])' %s\" \"Load %s Node.js module\"\n",
+				module.Path, module.Name,
+			)
+			data += "  exit 0\n"
+			data += "\n"
+		}
+	}
+
+	return data
+}
+
+// genPHPExtensionsCheck generates checks for PHP extensions
+func genPHPExtensionsCheck(info *data.Info) string {
+	var data string
+
+	for _, ext := range info.PHPExtensions {
+		data += fmt.Sprintf("command \"php --ri %s\" \"Check %s PHP extension\"\n", ext.Name, ext.Name)
+		data += "  exit 0\n"
+		data += "\n"
+	}
+
+	return data
+}
+
+// genLuaModulesCheck generates checks for loading Lua modules
+func genLuaModulesCheck(info *data.Info) string {
+	var data string
+
+	for _, module := range info.LuaModules {
+		data += fmt.Sprintf(
+			"command \"%s -l %s -e x=1\" \"Load %s Lua module\"\n",
+			module.Runtime, module.Name, module.Name,
+		)
+		data += "  exit 0\n"
+		data += "\n"
+	}
+
+	return data
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// getModulesDeps returns packages with interpreters required for module checks
+func getModulesDeps(info *data.Info) []string {
+	var result []string
+
+	if len(info.PerlModules) != 0 {
+		result = append(result, "perl")
+	}
+
+	if len(info.RubyGems) != 0 {
+		result = append(result, "ruby", "rubygems")
+	}
+
+	if len(info.NodeModules) != 0 {
+		result = append(result, "nodejs")
+	}
+
+	if len(info.PHPExtensions) != 0 {
+		result = append(result, "php-cli")
+	}
+
+	if len(info.LuaModules) != 0 {
+		result = append(result, "lua")
+	}
+
+	return result
+}
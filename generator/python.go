@@ -0,0 +1,342 @@
+package generator
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	PATH "github.com/essentialkaos/ek/v13/path"
+
+	"github.com/essentialkaos/bop/data"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// python3SiteDirRegex is regex for versioned Python 3 site-packages directory
+var python3SiteDirRegex = regexp.MustCompile(`^/usr(/local)?/lib(64)?/python3\.([0-9]+)/site-packages`)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// genPythonVariables generates variables with paths to site-packages directories
+// if packages contain modules for several or non-default versions of Python 3
+func genPythonVariables(info *data.Info) string {
+	if !isVersionedPython(info) {
+		return ""
+	}
+
+	var data string
+	var dirs []string
+
+	for _, path := range getPython3Paths(info) {
+		dir := python3SiteDirRegex.FindString(path)
+
+		if dir != "" && !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	slices.SortFunc(dirs, func(a, b string) int {
+		va, _ := strconv.Atoi(python3SiteDirRegex.FindStringSubmatch(a)[3])
+		vb, _ := strconv.Atoi(python3SiteDirRegex.FindStringSubmatch(b)[3])
+
+		if va != vb {
+			return va - vb
+		}
+
+		return strings.Compare(a, b)
+	})
+
+	for _, dir := range dirs {
+		data += fmt.Sprintf("var %s %s\n", getPython3SiteDirVariable(dir), dir)
+	}
+
+	return data + "\n"
+}
+
+// genPythonDistsCheck generates checks for Python distributions metadata and
+// console scripts
+func genPythonDistsCheck(info *data.Info) string {
+	if len(info.PythonDists) == 0 {
+		return ""
+	}
+
+	versioned := isVersionedPython(info)
+	checked := make(map[string]bool)
+	data := `command "-" "Check Python distributions"` + "\n"
+
+	for _, dist := range info.PythonDists {
+		data += fmt.Sprintf("  exist %s\n", getPythonModuleFilePath(dist.Path, versioned))
+
+		if dist.PkgVersion != "" {
+			data += fmt.Sprintf(
+				"  file-contains %s \"Version: %s\"\n",
+				getPythonModuleFilePath(dist.Metadata, versioned), dist.PkgVersion,
+			)
+		}
+
+		// The same script can be provided by distributions for different
+		// versions of Python
+		for _, script := range dist.Scripts {
+			if !checked[script] {
+				data += fmt.Sprintf("  app %s\n", script)
+				checked[script] = true
+			}
+		}
+
+		data += "\n"
+	}
+
+	return data
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// isVersionedPython returns true if packages contain modules for several
+// versions of Python 3 or only for version which is not default on distribution
+// (e.g. python3.11 on EL 9)
+func isVersionedPython(info *data.Info) bool {
+	var versions []string
+
+	for _, path := range getPython3Paths(info) {
+		match := python3SiteDirRegex.FindStringSubmatch(path)
+
+		if match != nil && !slices.Contains(versions, "3."+match[3]) {
+			versions = append(versions, "3."+match[3])
+		}
+	}
+
+	switch len(versions) {
+	case 0:
+		return false
+	case 1:
+		return info.Distro.IsKnown() && info.Distro.Python3Version != "" &&
+			versions[0] != info.Distro.Python3Version
+	}
+
+	return true
+}
+
+// getPython3Paths returns paths of all Python 3 objects
+func getPython3Paths(info *data.Info) []string {
+	var result []string
+
+	for _, obj := range append(slices.Clone(info.Python3Dirs), info.Python3Files...) {
+		result = append(result, obj.Path)
+	}
+
+	for _, dist := range info.PythonDists {
+		result = append(result, dist.Path)
+	}
+
+	return result
+}
+
+// getPython3SiteDirVariable returns name of variable for versioned Python 3
+// site-packages directory (e.g. /usr/lib64/python3.11/site-packages →
+// python3_11_sitearch)
+func getPython3SiteDirVariable(dir string) string {
+	match := python3SiteDirRegex.FindStringSubmatch(dir)
+
+	if match == nil {
+		return ""
+	}
+
+	name := "python3_" + match[3] + "_sitelib"
+
+	if match[2] != "" {
+		name = "python3_" + match[3] + "_sitearch"
+	}
+
+	if match[1] != "" {
+		name += "_local"
+	}
+
+	return name
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// pythonImports contains info about modules, extensions and distributions for
+// import tests with one Python interpreter
+type pythonImports struct {
+	Modules []string
+	Exts    []string
+	Dists   []*data.PythonDist
+}
+
+// Python code for import tests (without double quotes and commas, which are not
+// allowed in commands)
+const (
+	pythonImportCode        = `import sys;import importlib;[importlib.import_module(x) for x in sys.argv[1:]]`
+	pythonImportVersionCode = `import sys;import importlib;import importlib.metadata as m;[importlib.import_module(x) for x in sys.argv[2:]];print(m.version(sys.argv[1]))`
+	pythonImportLegacyCode  = `import sys;import importlib;import pkg_resources as m;[importlib.import_module(x) for x in sys.argv[2:]];print(m.get_distribution(sys.argv[1]).version)`
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// genPythonImportsCheck generates checks for importing Python modules and
+// compiled extensions and checking distributions versions
+func genPythonImportsCheck(info *data.Info) string {
+	var data string
+
+	if !info.Distro.HasPython3() {
+		return ""
+	}
+
+	imports := getPythonImports(info)
+
+	versions := slices.Collect(maps.Keys(imports))
+
+	slices.SortFunc(versions, func(a, b string) int {
+		va, _ := strconv.Atoi(strings.TrimPrefix(a, "3."))
+		vb, _ := strconv.Atoi(strings.TrimPrefix(b, "3."))
+		return va - vb
+	})
+
+	for _, version := range versions {
+		imp := imports[version]
+		python, desc := "python3", "Python 3"
+
+		if version != "" {
+			python, desc = "python"+version, "Python "+version
+		}
+
+		versionCode := pythonImportVersionCode
+
+		if isLegacyPython(info, version) {
+			versionCode = pythonImportLegacyCode
+		}
+
+		switch {
+		case len(imp.Dists) != 0:
+			for i, dist := range imp.Dists {
+				modules := ""
+
+				if i == 0 && len(imp.Modules) != 0 {
+					modules = " " + strings.Join(imp.Modules, " ")
+				}
+
+				data += fmt.Sprintf(
+					"command \"%s -c '%s' %s%s\" \"Import %s modules with %s and check version\"\n",
+					python, versionCode, dist.Name, modules, dist.Name, desc,
+				)
+				data += "  exit 0\n"
+				data += fmt.Sprintf("  expect \"%s\"\n", dist.PkgVersion)
+				data += "\n"
+			}
+
+		case len(imp.Modules) != 0:
+			data += fmt.Sprintf(
+				"command \"%s -c '%s' %s\" \"Import modules with %s\"\n",
+				python, pythonImportCode, strings.Join(imp.Modules, " "), desc,
+			)
+			data += "  exit 0\n"
+			data += "\n"
+		}
+
+		if len(imp.Exts) != 0 {
+			data += fmt.Sprintf(
+				"command \"%s -c '%s' %s\" \"Import compiled extensions with %s\"\n",
+				python, pythonImportCode, strings.Join(imp.Exts, " "), desc,
+			)
+			data += "  exit 0\n"
+			data += "\n"
+		}
+	}
+
+	return data
+}
+
+// getPythonImports returns info for import tests grouped by Python version
+// (empty version means default Python 3 interpreter)
+func getPythonImports(info *data.Info) map[string]*pythonImports {
+	result := make(map[string]*pythonImports)
+	versioned := isVersionedPython(info)
+
+	get := func(path string) *pythonImports {
+		version := ""
+
+		if versioned {
+			match := python3SiteDirRegex.FindStringSubmatch(path)
+
+			if match != nil {
+				version = "3." + match[3]
+			}
+		}
+
+		if result[version] == nil {
+			result[version] = &pythonImports{}
+		}
+
+		return result[version]
+	}
+
+	for _, dir := range info.Python3Dirs {
+		_, module, ok := strings.Cut(dir.Path, "/site-packages/")
+		module, _, _ = strings.Cut(module, "/")
+
+		if !ok || module == "" {
+			continue
+		}
+
+		imp := get(dir.Path)
+
+		if !slices.Contains(imp.Modules, module) {
+			imp.Modules = append(imp.Modules, module)
+		}
+	}
+
+	for _, ext := range info.Python3Exts {
+		_, module, ok := strings.Cut(ext, "/site-packages/")
+
+		if !ok {
+			continue
+		}
+
+		// foo/_speedups.cpython-311-x86_64-linux-gnu.so → foo._speedups
+		dir, file := PATH.Dir(module), PATH.Base(module)
+		module, _, _ = strings.Cut(file, ".")
+
+		if dir != "." {
+			module = strings.ReplaceAll(dir, "/", ".") + "." + module
+		}
+
+		imp := get(ext)
+		imp.Exts = append(imp.Exts, module)
+	}
+
+	for _, dist := range info.PythonDists {
+		if python3SiteDirRegex.MatchString(dist.Path) {
+			imp := get(dist.Path)
+			imp.Dists = append(imp.Dists, dist)
+		}
+	}
+
+	for _, imp := range result {
+		slices.Sort(imp.Modules)
+		slices.Sort(imp.Exts)
+	}
+
+	return result
+}
+
+// isLegacyPython returns true if Python doesn't have importlib.metadata module
+// (Python < 3.8)
+func isLegacyPython(info *data.Info, version string) bool {
+	if version != "" {
+		minor, _ := strconv.Atoi(strings.TrimPrefix(version, "3."))
+		return minor < 8
+	}
+
+	return info.Distro.IsKnown() && info.Distro.LegacyPython3
+}
@@ -0,0 +1,212 @@
+package generator
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/essentialkaos/bop/data"
+	"github.com/essentialkaos/bop/systemd"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// systemdUnitDir is path to directory with system units
+const systemdUnitDir = "/usr/lib/systemd/system"
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// genUnitsPresenceCheck generates checks for socket, timer, path and target units
+// presence
+func genUnitsPresenceCheck(info *data.Info) string {
+	if !hasExtraUnits(info) {
+		return ""
+	}
+
+	data := `command "-" "Check systemd units presence"` + "\n"
+
+	for _, units := range [][]string{info.Sockets, info.Timers, info.PathUnits, info.Targets} {
+		for _, unit := range units {
+			data += fmt.Sprintf("  exist %s/%s\n", systemdUnitDir, unit)
+		}
+	}
+
+	return data + "\n"
+}
+
+// genUnitsCheck generates checks for socket, timer, path and target units
+func genUnitsCheck(info *data.Info, services []string) string {
+	if !info.Distro.IsSystemd() || !hasExtraUnits(info) {
+		return ""
+	}
+
+	var data string
+
+	for _, unit := range filterUnits(info.Sockets, services) {
+		data += genSocketCheck(unit, info.Units[unit])
+	}
+
+	for _, unit := range filterUnits(info.Timers, services) {
+		data += genTimerCheck(unit)
+	}
+
+	for _, unit := range filterUnits(info.PathUnits, services) {
+		data += genPathUnitCheck(unit)
+	}
+
+	for _, unit := range filterUnits(info.Targets, services) {
+		data += genTargetCheck(unit)
+	}
+
+	return data
+}
+
+// genSocketCheck generates checks for socket unit
+func genSocketCheck(name string, unit *systemd.Unit) string {
+	baseName := strings.TrimSuffix(name, path.Ext(name))
+
+	units := name
+	data := fmt.Sprintf("command \"systemctl start %s\" \"Start %s socket\"\n", name, baseName)
+	data += "  exit 0\n"
+
+	if unit != nil && unit.Socket != nil {
+		for _, listen := range unit.Socket.ListenStream {
+			data += genListenCheck(listen)
+		}
+
+		// Connection to socket activates service which isn't stopped with socket
+		switch {
+		case unit.Socket.Service == "":
+			// nothing to stop
+		case unit.Socket.Accept:
+			units += " " + strings.Replace(unit.Socket.Service, "@.service", "@*.service", 1)
+		default:
+			units += " " + unit.Socket.Service
+		}
+	}
+
+	data += "\n"
+	data += fmt.Sprintf("command \"systemctl is-active %s\" \"Check status of %s socket\"\n", name, baseName)
+	data += "  exit 0\n"
+	data += "\n"
+	data += fmt.Sprintf("command \"systemctl stop %s\" \"Stop %s socket\"\n", units, baseName)
+	data += "  exit 0\n"
+
+	return data + "\n"
+}
+
+// genListenCheck generates check for socket listen address
+func genListenCheck(listen string) string {
+	switch {
+	case strings.Contains(listen, "%"):
+		// Specifiers are resolved by systemd
+		return ""
+	case strings.HasPrefix(listen, "/"):
+		return fmt.Sprintf("  wait-fs %s\n", listen)
+	}
+
+	port := data.ParsePort(listen)
+
+	if port == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("  connect tcp %s\n", port.Address())
+}
+
+// genTimerCheck generates checks for timer unit
+func genTimerCheck(name string) string {
+	baseName := strings.TrimSuffix(name, path.Ext(name))
+
+	data := fmt.Sprintf("command \"systemctl enable --now %s\" \"Enable %s timer\"\n", name, baseName)
+	data += "  exit 0\n"
+	data += "\n"
+	data += fmt.Sprintf("command \"systemctl list-timers --all %s\" \"Check %s timer schedule\"\n", name, baseName)
+	data += "  exit 0\n"
+	data += fmt.Sprintf("  expect \"%s\"\n", name)
+	data += "\n"
+	data += fmt.Sprintf("command \"systemctl disable --now %s\" \"Disable %s timer\"\n", name, baseName)
+	data += "  exit 0\n"
+
+	return data + "\n"
+}
+
+// genPathUnitCheck generates checks for path unit
+func genPathUnitCheck(name string) string {
+	baseName := strings.TrimSuffix(name, path.Ext(name))
+
+	data := fmt.Sprintf("command \"systemctl enable --now %s\" \"Enable %s path unit\"\n", name, baseName)
+	data += "  exit 0\n"
+	data += "\n"
+	data += fmt.Sprintf("command \"systemctl is-active %s\" \"Check %s path unit state\"\n", name, baseName)
+	data += "  exit 0\n"
+	data += "\n"
+	data += fmt.Sprintf("command \"systemctl disable --now %s\" \"Disable %s path unit\"\n", name, baseName)
+	data += "  exit 0\n"
+
+	return data + "\n"
+}
+
+// genTargetCheck generates checks for target unit
+func genTargetCheck(name string) string {
+	baseName := strings.TrimSuffix(name, path.Ext(name))
+
+	data := fmt.Sprintf("command \"systemctl show -p LoadState %s\" \"Check %s target\"\n", name, baseName)
+	data += "  exit 0\n"
+	data += "  expect \"LoadState=loaded\"\n"
+
+	return data + "\n"
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// hasExtraUnits returns true if info contains socket, timer, path or target units
+func hasExtraUnits(info *data.Info) bool {
+	return len(info.Sockets)+len(info.Timers)+len(info.PathUnits)+len(info.Targets) != 0
+}
+
+// isTriggeredService returns true if service is started by timer or path unit
+func isTriggeredService(info *data.Info, service string) bool {
+	for _, name := range append(slices.Clone(info.Timers), info.PathUnits...) {
+		unit := info.Units[name]
+
+		switch {
+		case unit == nil && strings.TrimSuffix(name, path.Ext(name)) == service,
+			unit != nil && unit.Timer != nil && unit.Timer.Unit == service+".service",
+			unit != nil && unit.Path != nil && unit.Path.Unit == service+".service":
+			return true
+		}
+	}
+
+	return false
+}
+
+// filterUnits returns units which must be checked (skipping templates and units
+// not mentioned in services list)
+func filterUnits(units, services []string) []string {
+	var result []string
+
+	for _, unit := range units {
+		name := strings.TrimSuffix(unit, path.Ext(unit))
+
+		if strings.HasSuffix(name, "@") {
+			continue
+		}
+
+		if len(services) > 0 && !slices.Contains(services, name) && !slices.Contains(services, unit) {
+			continue
+		}
+
+		result = append(result, unit)
+	}
+
+	return result
+}
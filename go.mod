@@ -2,9 +2,17 @@ module github.com/essentialkaos/bop
 
 go 1.23.6
 
-require github.com/essentialkaos/ek/v13 v13.25.0
+require (
+	github.com/essentialkaos/check v1.4.1
+	github.com/essentialkaos/ek/v13 v13.25.0
+	github.com/klauspost/compress v1.18.0
+	github.com/ulikunitz/xz v0.5.15
+)
 
 require (
 	github.com/essentialkaos/depsy v1.3.1 // indirect
+	github.com/kr/pretty v0.3.1 // indirect
+	github.com/kr/text v0.2.0 // indirect
+	github.com/rogpeppe/go-internal v1.14.1 // indirect
 	golang.org/x/sys v0.33.0 // indirect
 )
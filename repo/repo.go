@@ -0,0 +1,489 @@
+package repo
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/essentialkaos/ek/v13/fsutil"
+
+	"github.com/essentialkaos/bop/rpm"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Repository contains info from local yum/dnf repository metadata
+type Repository struct {
+	dir       string
+	primary   string
+	filelists string
+	packages  []*repoPackage
+	files     map[string]*repoPackage
+}
+
+// repoPackage contains info about package from repository metadata
+type repoPackage struct {
+	id       string
+	file     string
+	pkg      *rpm.Package
+	payload  []*rpm.Object
+	selected bool
+	loaded   bool
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// repomdXML contains repository index
+type repomdXML struct {
+	Data []struct {
+		Type     string      `xml:"type,attr"`
+		Location locationXML `xml:"location"`
+	} `xml:"data"`
+}
+
+// primaryPackageXML contains package info from primary metadata
+type primaryPackageXML struct {
+	Type     string      `xml:"type,attr"`
+	Name     string      `xml:"name"`
+	Arch     string      `xml:"arch"`
+	Version  versionXML  `xml:"version"`
+	Checksum string      `xml:"checksum"`
+	Summary  string      `xml:"summary"`
+	URL      string      `xml:"url"`
+	Location locationXML `xml:"location"`
+	Time     struct {
+		Build int64 `xml:"build,attr"`
+	} `xml:"time"`
+	Format struct {
+		License     string     `xml:"license"`
+		Vendor      string     `xml:"vendor"`
+		SourceRPM   string     `xml:"sourcerpm"`
+		Provides    []entryXML `xml:"provides>entry"`
+		Requires    []entryXML `xml:"requires>entry"`
+		Conflicts   []entryXML `xml:"conflicts>entry"`
+		Obsoletes   []entryXML `xml:"obsoletes>entry"`
+		Recommends  []entryXML `xml:"recommends>entry"`
+		Suggests    []entryXML `xml:"suggests>entry"`
+		Supplements []entryXML `xml:"supplements>entry"`
+		Enhances    []entryXML `xml:"enhances>entry"`
+	} `xml:"format"`
+}
+
+// filelistsPackageXML contains info about package files from filelists metadata
+type filelistsPackageXML struct {
+	ID    string    `xml:"pkgid,attr"`
+	Files []fileXML `xml:"file"`
+}
+
+// locationXML contains location of file
+type locationXML struct {
+	Href string `xml:"href,attr"`
+	Base string `xml:"base,attr"`
+}
+
+// versionXML contains package version info
+type versionXML struct {
+	Epoch string `xml:"epoch,attr"`
+	Ver   string `xml:"ver,attr"`
+	Rel   string `xml:"rel,attr"`
+}
+
+// entryXML contains info about dependency
+type entryXML struct {
+	Name  string `xml:"name,attr"`
+	Flags string `xml:"flags,attr"`
+	Epoch string `xml:"epoch,attr"`
+	Ver   string `xml:"ver,attr"`
+	Rel   string `xml:"rel,attr"`
+	Pre   string `xml:"pre,attr"`
+}
+
+// fileXML contains info about package file
+type fileXML struct {
+	Type string `xml:"type,attr"`
+	Path string `xml:",chardata"`
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// depFlags is map metadata comparison flags → rpm sense flags
+var depFlags = map[string]int64{
+	"LT": rpm.SENSE_LESS,
+	"LE": rpm.SENSE_LESS | rpm.SENSE_EQUAL,
+	"EQ": rpm.SENSE_EQUAL,
+	"GE": rpm.SENSE_GREATER | rpm.SENSE_EQUAL,
+	"GT": rpm.SENSE_GREATER,
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// IsRepository returns true if given directory contains repository metadata
+func IsRepository(dir string) bool {
+	return fsutil.IsDir(dir) && fsutil.IsExist(filepath.Join(dir, "repodata/repomd.xml"))
+}
+
+// Open reads metadata of repository in given directory
+func Open(dir string) (*Repository, error) {
+	r := &Repository{dir: dir, files: make(map[string]*repoPackage)}
+
+	err := r.readIndex()
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.readPrimary()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// FindBySource returns paths of binary packages built from source package with
+// given name
+func (r *Repository) FindBySource(name string) []string {
+	var result []string
+
+	for _, p := range r.packages {
+		if p.pkg.IsSrc || p.pkg.SourceName() != name {
+			continue
+		}
+
+		p.selected = true
+		result = append(result, p.file)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+// Sources returns names of all source packages in repository
+func (r *Repository) Sources() []string {
+	var result []string
+
+	sources := make(map[string]bool)
+
+	for _, p := range r.packages {
+		name := p.pkg.SourceName()
+
+		if !sources[name] {
+			sources[name] = true
+			result = append(result, name)
+		}
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+// Name returns name of reader
+func (r *Repository) Name() string {
+	return "repo"
+}
+
+// IsSupported returns true if reader can be used on current system
+func (r *Repository) IsSupported() bool {
+	return r != nil
+}
+
+// IsPackage returns true if repository contains package with given path
+func (r *Repository) IsPackage(file string) bool {
+	return r.files[file] != nil
+}
+
+// ReadInfo reads package metadata
+func (r *Repository) ReadInfo(file string) (*rpm.Package, error) {
+	p := r.files[file]
+
+	if p == nil {
+		return nil, fmt.Errorf("Repository %s doesn't contain package %s", r.dir, file)
+	}
+
+	pkg := *p.pkg
+
+	return &pkg, nil
+}
+
+// Read reads package info with info about payload
+func (r *Repository) Read(file string) (*rpm.Package, error) {
+	p := r.files[file]
+
+	if p == nil {
+		return nil, fmt.Errorf("Repository %s doesn't contain package %s", r.dir, file)
+	}
+
+	if !p.loaded {
+		p.selected = true
+		err := r.readFilelists()
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pkg := *p.pkg
+	pkg.Payload = p.payload
+
+	return &pkg, nil
+}
+
+// ReadFiles returns error because repository metadata doesn't contain content
+// of payload files
+func (r *Repository) ReadFiles(file string, paths []string, handler rpm.FileHandler) error {
+	return rpm.ErrNoContent
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// readIndex reads repository index (repomd.xml)
+func (r *Repository) readIndex() error {
+	data, err := os.ReadFile(filepath.Join(r.dir, "repodata/repomd.xml"))
+
+	if err != nil {
+		return err
+	}
+
+	index := &repomdXML{}
+
+	err = xml.Unmarshal(data, index)
+
+	if err != nil {
+		return fmt.Errorf("Can't parse repository index: %w", err)
+	}
+
+	for _, d := range index.Data {
+		switch d.Type {
+		case "primary":
+			r.primary = filepath.Join(r.dir, d.Location.Href)
+		case "filelists":
+			r.filelists = filepath.Join(r.dir, d.Location.Href)
+		}
+	}
+
+	if r.primary == "" {
+		return fmt.Errorf("Repository %s doesn't contain primary metadata", r.dir)
+	}
+
+	return nil
+}
+
+// readPrimary reads primary metadata
+func (r *Repository) readPrimary() error {
+	return decodeMetadata(r.primary, func(dec *xml.Decoder, se *xml.StartElement) error {
+		info := &primaryPackageXML{}
+		err := dec.DecodeElement(info, se)
+
+		if err != nil || info.Type != "rpm" {
+			return err
+		}
+
+		p := &repoPackage{
+			id:   info.Checksum,
+			file: filepath.Join(r.dir, info.Location.Href),
+			pkg:  convertPackageInfo(info),
+		}
+
+		// Package located on remote server
+		if info.Location.Base != "" && !strings.HasPrefix(info.Location.Base, "file://") {
+			p.file = strings.TrimRight(info.Location.Base, "/") + "/" + info.Location.Href
+		}
+
+		r.packages = append(r.packages, p)
+		r.files[p.file] = p
+
+		return nil
+	})
+}
+
+// readFilelists reads info about files of selected packages from filelists
+// metadata
+func (r *Repository) readFilelists() error {
+	if r.filelists == "" {
+		return fmt.Errorf("Repository %s doesn't contain filelists metadata", r.dir)
+	}
+
+	ids := make(map[string]*repoPackage)
+
+	for _, p := range r.packages {
+		if p.selected && !p.loaded {
+			ids[p.id] = p
+		}
+	}
+
+	err := decodeMetadata(r.filelists, func(dec *xml.Decoder, se *xml.StartElement) error {
+		info := &filelistsPackageXML{}
+		err := dec.DecodeElement(info, se)
+
+		if err != nil || ids[info.ID] == nil {
+			return err
+		}
+
+		ids[info.ID].payload = convertFilesInfo(info.Files)
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for _, p := range ids {
+		p.loaded = true
+	}
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// decodeMetadata decodes every package element in compressed metadata file
+func decodeMetadata(file string, handler func(*xml.Decoder, *xml.StartElement) error) error {
+	fd, err := os.Open(file)
+
+	if err != nil {
+		return err
+	}
+
+	defer fd.Close()
+
+	r, err := rpm.NewDecompressor(fd)
+
+	if err != nil {
+		return fmt.Errorf("Can't decompress %s: %w", file, err)
+	}
+
+	defer r.Close()
+
+	dec := xml.NewDecoder(r)
+
+	for {
+		token, err := dec.Token()
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("Can't parse %s: %w", file, err)
+		}
+
+		se, ok := token.(xml.StartElement)
+
+		if !ok || se.Name.Local != "package" {
+			continue
+		}
+
+		err = handler(dec, &se)
+
+		if err != nil {
+			return fmt.Errorf("Can't parse %s: %w", file, err)
+		}
+	}
+}
+
+// convertPackageInfo converts package info from metadata to package struct
+func convertPackageInfo(info *primaryPackageXML) *rpm.Package {
+	pkg := &rpm.Package{
+		Name:      info.Name,
+		Version:   info.Version.Ver,
+		Release:   info.Version.Rel,
+		Arch:      info.Arch,
+		Dist:      rpm.ExtractDist(info.Version.Rel),
+		SourceRPM: info.Format.SourceRPM,
+		Summary:   info.Summary,
+		URL:       info.URL,
+		License:   info.Format.License,
+		Vendor:    info.Format.Vendor,
+		BuildTime: time.Unix(info.Time.Build, 0),
+		IsSrc:     info.Arch == "src" || info.Arch == "nosrc",
+	}
+
+	if info.Version.Epoch != "0" {
+		pkg.Epoch = info.Version.Epoch
+	}
+
+	deps := map[string][]entryXML{
+		rpm.DEP_PROVIDES:    info.Format.Provides,
+		rpm.DEP_REQUIRES:    info.Format.Requires,
+		rpm.DEP_CONFLICTS:   info.Format.Conflicts,
+		rpm.DEP_OBSOLETES:   info.Format.Obsoletes,
+		rpm.DEP_RECOMMENDS:  info.Format.Recommends,
+		rpm.DEP_SUGGESTS:    info.Format.Suggests,
+		rpm.DEP_SUPPLEMENTS: info.Format.Supplements,
+		rpm.DEP_ENHANCES:    info.Format.Enhances,
+	}
+
+	for kind, entries := range deps {
+		for _, entry := range entries {
+			pkg.Deps.Add(kind, convertDepInfo(entry))
+		}
+	}
+
+	return pkg
+}
+
+// convertDepInfo converts dependency info from metadata to dependency struct
+func convertDepInfo(entry entryXML) *rpm.Dependency {
+	dep := &rpm.Dependency{
+		Name:  entry.Name,
+		Flags: depFlags[entry.Flags],
+	}
+
+	if entry.Pre == "1" {
+		dep.Flags |= rpm.SENSE_PREREQ
+	}
+
+	if entry.Ver != "" {
+		dep.Version = entry.Ver
+
+		if entry.Epoch != "" && entry.Epoch != "0" {
+			dep.Version = entry.Epoch + ":" + dep.Version
+		}
+
+		if entry.Rel != "" {
+			dep.Version += "-" + entry.Rel
+		}
+	}
+
+	return dep
+}
+
+// convertFilesInfo converts info about files from metadata to payload objects.
+// Metadata doesn't contain info about modes, owners, digests and symlinks, so
+// objects are marked as objects with unknown metadata.
+func convertFilesInfo(files []fileXML) []*rpm.Object {
+	var result []*rpm.Object
+
+	for _, file := range files {
+		// Ghost files may not exist after package installation
+		if file.Type == "ghost" {
+			continue
+		}
+
+		result = append(result, &rpm.Object{
+			Path:   file.Path,
+			IsDir:  file.Type == "dir",
+			NoMeta: true,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,174 @@
+package repo
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"os"
+	"testing"
+
+	"github.com/essentialkaos/bop/rpm"
+
+	. "github.com/essentialkaos/check"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func Test(t *testing.T) { TestingT(t) }
+
+type RepoSuite struct{}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var _ = Suite(&RepoSuite{})
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (s *RepoSuite) TestOpen(c *C) {
+	c.Assert(IsRepository("testdata"), Equals, true)
+	c.Assert(IsRepository(c.MkDir()), Equals, false)
+
+	r, err := Open("testdata")
+
+	c.Assert(err, IsNil)
+	c.Assert(r.Name(), Equals, "repo")
+	c.Assert(r.IsSupported(), Equals, true)
+	c.Assert(r.packages, HasLen, 4)
+	c.Assert(r.Sources(), DeepEquals, []string{"app", "libfoo"})
+
+	c.Assert(r.IsPackage("testdata/Packages/a/app-1.0-1.el9.x86_64.rpm"), Equals, true)
+	c.Assert(r.IsPackage("https://mirror.example.com/el9/Packages/a/app-devel-1.0-1.el9.x86_64.rpm"), Equals, true)
+	c.Assert(r.IsPackage("testdata/Packages/a/app-devel-1.0-1.el9.x86_64.rpm"), Equals, false)
+}
+
+func (s *RepoSuite) TestOpenErrors(c *C) {
+	dir := c.MkDir()
+
+	_, err := Open(dir)
+	c.Assert(err, NotNil)
+
+	c.Assert(os.Mkdir(dir+"/repodata", 0755), IsNil)
+	c.Assert(os.WriteFile(dir+"/repodata/repomd.xml", []byte("<repomd"), 0644), IsNil)
+
+	_, err = Open(dir)
+	c.Assert(err, ErrorMatches, "Can't parse repository index: .*")
+
+	c.Assert(os.WriteFile(dir+"/repodata/repomd.xml", []byte(
+		`<repomd><data type="filelists"><location href="repodata/filelists.xml"/></data></repomd>`,
+	), 0644), IsNil)
+
+	_, err = Open(dir)
+	c.Assert(err, ErrorMatches, "Repository .* doesn't contain primary metadata")
+
+	c.Assert(os.WriteFile(dir+"/repodata/repomd.xml", []byte(
+		`<repomd><data type="primary"><location href="repodata/primary.xml"/></data></repomd>`,
+	), 0644), IsNil)
+
+	_, err = Open(dir)
+	c.Assert(err, NotNil)
+
+	c.Assert(os.WriteFile(dir+"/repodata/primary.xml", []byte(
+		`<metadata><package type="rpm"><name>app</name><location href="app.rpm"/></package></metadata>`,
+	), 0644), IsNil)
+
+	r, err := Open(dir)
+	c.Assert(err, IsNil)
+
+	_, err = r.Read(dir + "/app.rpm")
+	c.Assert(err, ErrorMatches, "Repository .* doesn't contain filelists metadata")
+}
+
+func (s *RepoSuite) TestFindBySource(c *C) {
+	r, err := Open("testdata")
+	c.Assert(err, IsNil)
+
+	c.Assert(r.FindBySource("app"), DeepEquals, []string{
+		"https://mirror.example.com/el9/Packages/a/app-devel-1.0-1.el9.x86_64.rpm",
+		"testdata/Packages/a/app-1.0-1.el9.x86_64.rpm",
+	})
+
+	c.Assert(r.FindBySource("libfoo"), DeepEquals, []string{
+		"testdata/Packages/l/libfoo-2.0-3.el9.x86_64.rpm",
+	})
+
+	c.Assert(r.FindBySource("unknown"), IsNil)
+}
+
+func (s *RepoSuite) TestReadInfo(c *C) {
+	r, err := Open("testdata")
+	c.Assert(err, IsNil)
+
+	pkg, err := r.ReadInfo("testdata/Packages/a/app-1.0-1.el9.x86_64.rpm")
+
+	c.Assert(err, IsNil)
+	c.Assert(pkg.NEVRA(), Equals, "app-1.0-1.el9.x86_64")
+	c.Assert(pkg.Dist, Equals, "el9")
+	c.Assert(pkg.Summary, Equals, "Test application")
+	c.Assert(pkg.License, Equals, "Apache-2.0")
+	c.Assert(pkg.BuildTime.Unix(), Equals, int64(1700000000))
+	c.Assert(pkg.SourceName(), Equals, "app")
+	c.Assert(pkg.IsSrc, Equals, false)
+	c.Assert(pkg.Payload, IsNil)
+
+	c.Assert(pkg.Deps.Provides, DeepEquals, []*rpm.Dependency{
+		{Name: "app", Version: "1.0-1.el9", Flags: rpm.SENSE_EQUAL},
+	})
+
+	c.Assert(pkg.Deps.Requires, DeepEquals, []*rpm.Dependency{
+		{Name: "bash", Flags: rpm.SENSE_PREREQ},
+		{Name: "libfoo", Version: "1:2.0-3", Flags: rpm.SENSE_GREATER | rpm.SENSE_EQUAL},
+		{Name: "libbar", Version: "3.0", Flags: rpm.SENSE_LESS},
+	})
+
+	c.Assert(pkg.Deps.Requires[0].IsPre(), Equals, true)
+
+	pkg, err = r.ReadInfo("testdata/Packages/l/libfoo-2.0-3.el9.x86_64.rpm")
+
+	c.Assert(err, IsNil)
+	c.Assert(pkg.Epoch, Equals, "2")
+	c.Assert(pkg.EVR(), Equals, "2:2.0-3.el9")
+
+	pkg, err = r.ReadInfo("testdata/Packages/a/app-1.0-1.el9.src.rpm")
+
+	c.Assert(err, IsNil)
+	c.Assert(pkg.IsSrc, Equals, true)
+	c.Assert(pkg.SourceName(), Equals, "app")
+
+	_, err = r.ReadInfo("testdata/Packages/u/unknown-1.0-1.el9.x86_64.rpm")
+	c.Assert(err, NotNil)
+}
+
+func (s *RepoSuite) TestRead(c *C) {
+	r, err := Open("testdata")
+	c.Assert(err, IsNil)
+
+	r.FindBySource("libfoo")
+
+	pkg, err := r.Read("testdata/Packages/a/app-1.0-1.el9.x86_64.rpm")
+
+	c.Assert(err, IsNil)
+	c.Assert(pkg.Payload, DeepEquals, []*rpm.Object{
+		{Path: "/usr/bin/app", NoMeta: true},
+		{Path: "/etc/app", IsDir: true, NoMeta: true},
+	})
+
+	// Files of selected packages are read in the same pass
+	lib := r.files["testdata/Packages/l/libfoo-2.0-3.el9.x86_64.rpm"]
+
+	c.Assert(lib.loaded, Equals, true)
+	c.Assert(lib.payload, HasLen, 1)
+	c.Assert(r.files["testdata/Packages/a/app-1.0-1.el9.src.rpm"].loaded, Equals, false)
+
+	// Package info in repository isn't modified
+	c.Assert(r.files["testdata/Packages/a/app-1.0-1.el9.x86_64.rpm"].pkg.Payload, IsNil)
+
+	_, err = r.Read("testdata/Packages/u/unknown-1.0-1.el9.x86_64.rpm")
+	c.Assert(err, NotNil)
+
+	err = r.ReadFiles("testdata/Packages/a/app-1.0-1.el9.x86_64.rpm", []string{"/usr/bin/app"}, nil)
+	c.Assert(err, Equals, rpm.ErrNoContent)
+}
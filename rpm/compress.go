@@ -0,0 +1,77 @@
+package rpm
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+	"github.com/ulikunitz/xz/lzma"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	magicGzip  = []byte{0x1F, 0x8B}
+	magicBzip2 = []byte("BZh")
+	magicXZ    = []byte{0xFD, '7', 'z', 'X', 'Z', 0x00}
+	magicZstd  = []byte{0x28, 0xB5, 0x2F, 0xFD}
+	magicLZMA  = []byte{0x5D, 0x00, 0x00}
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewDecompressor returns reader which decompresses data from given reader.
+// Compression format (gzip, bzip2, xz, lzma or zstd) is detected by magic bytes,
+// uncompressed data is returned as is.
+func NewDecompressor(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	magic, _ := br.Peek(6)
+
+	switch {
+	case bytes.HasPrefix(magic, magicGzip):
+		return gzip.NewReader(br)
+
+	case bytes.HasPrefix(magic, magicBzip2):
+		return io.NopCloser(bzip2.NewReader(br)), nil
+
+	case bytes.HasPrefix(magic, magicXZ):
+		xr, err := xz.NewReader(br)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return io.NopCloser(xr), nil
+
+	case bytes.HasPrefix(magic, magicZstd):
+		zr, err := zstd.NewReader(br)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return zr.IOReadCloser(), nil
+
+	case bytes.HasPrefix(magic, magicLZMA):
+		lr, err := lzma.NewReader(br)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return io.NopCloser(lr), nil
+	}
+
+	return io.NopCloser(br), nil
+}
@@ -0,0 +1,226 @@
+package rpm
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"strings"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Dependency kinds
+const (
+	DEP_PROVIDES    = "provides"
+	DEP_REQUIRES    = "requires"
+	DEP_CONFLICTS   = "conflicts"
+	DEP_OBSOLETES   = "obsoletes"
+	DEP_RECOMMENDS  = "recommends"
+	DEP_SUGGESTS    = "suggests"
+	DEP_SUPPLEMENTS = "supplements"
+	DEP_ENHANCES    = "enhances"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Dependency contains info about package capability
+type Dependency struct {
+	Name    string
+	Version string
+	Flags   int64
+}
+
+// Dependencies contains all package capabilities
+type Dependencies struct {
+	Provides    []*Dependency
+	Requires    []*Dependency
+	Conflicts   []*Dependency
+	Obsoletes   []*Dependency
+	Recommends  []*Dependency
+	Suggests    []*Dependency
+	Supplements []*Dependency
+	Enhances    []*Dependency
+}
+
+// depTag contains name, flags and version tags for dependency kind
+type depTag struct {
+	kind    string
+	name    int32
+	flags   int32
+	version int32
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// depTags contains tags for every dependency kind
+var depTags = []depTag{
+	{DEP_PROVIDES, TAG_PROVIDENAME, TAG_PROVIDEFLAGS, TAG_PROVIDEVERSION},
+	{DEP_REQUIRES, TAG_REQUIRENAME, TAG_REQUIREFLAGS, TAG_REQUIREVERSION},
+	{DEP_CONFLICTS, TAG_CONFLICTNAME, TAG_CONFLICTFLAGS, TAG_CONFLICTVERSION},
+	{DEP_OBSOLETES, TAG_OBSOLETENAME, TAG_OBSOLETEFLAGS, TAG_OBSOLETEVERSION},
+	{DEP_RECOMMENDS, TAG_RECOMMENDNAME, TAG_RECOMMENDFLAGS, TAG_RECOMMENDVERSION},
+	{DEP_SUGGESTS, TAG_SUGGESTNAME, TAG_SUGGESTFLAGS, TAG_SUGGESTVERSION},
+	{DEP_SUPPLEMENTS, TAG_SUPPLEMENTNAME, TAG_SUPPLEMENTFLAGS, TAG_SUPPLEMENTVERSION},
+	{DEP_ENHANCES, TAG_ENHANCENAME, TAG_ENHANCEFLAGS, TAG_ENHANCEVERSION},
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Get returns dependencies with given kind
+func (d *Dependencies) Get(kind string) []*Dependency {
+	if d == nil {
+		return nil
+	}
+
+	switch kind {
+	case DEP_PROVIDES:
+		return d.Provides
+	case DEP_REQUIRES:
+		return d.Requires
+	case DEP_CONFLICTS:
+		return d.Conflicts
+	case DEP_OBSOLETES:
+		return d.Obsoletes
+	case DEP_RECOMMENDS:
+		return d.Recommends
+	case DEP_SUGGESTS:
+		return d.Suggests
+	case DEP_SUPPLEMENTS:
+		return d.Supplements
+	case DEP_ENHANCES:
+		return d.Enhances
+	}
+
+	return nil
+}
+
+// Add adds dependency with given kind
+func (d *Dependencies) Add(kind string, dep *Dependency) {
+	if d == nil || dep == nil {
+		return
+	}
+
+	switch kind {
+	case DEP_PROVIDES:
+		d.Provides = append(d.Provides, dep)
+	case DEP_REQUIRES:
+		d.Requires = append(d.Requires, dep)
+	case DEP_CONFLICTS:
+		d.Conflicts = append(d.Conflicts, dep)
+	case DEP_OBSOLETES:
+		d.Obsoletes = append(d.Obsoletes, dep)
+	case DEP_RECOMMENDS:
+		d.Recommends = append(d.Recommends, dep)
+	case DEP_SUGGESTS:
+		d.Suggests = append(d.Suggests, dep)
+	case DEP_SUPPLEMENTS:
+		d.Supplements = append(d.Supplements, dep)
+	case DEP_ENHANCES:
+		d.Enhances = append(d.Enhances, dep)
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Op returns comparison operator of dependency
+func (d *Dependency) Op() string {
+	return formatSenseFlags(d.Flags)
+}
+
+// IsPre returns true if dependency is required for pre/post scriptlets
+func (d *Dependency) IsPre() bool {
+	return d.Flags&(SENSE_PREREQ|SENSE_SCRIPT_PRE|SENSE_SCRIPT_POST) != 0
+}
+
+// IsRich returns true if dependency is a rich (boolean) dependency
+func (d *Dependency) IsRich() bool {
+	return strings.HasPrefix(d.Name, "(")
+}
+
+// IsInternal returns true if dependency is internal rpm dependency (rpmlib,
+// config, etc.)
+func (d *Dependency) IsInternal() bool {
+	return d.Flags&SENSE_RPMLIB != 0 ||
+		strings.HasPrefix(d.Name, "rpmlib(") ||
+		strings.HasPrefix(d.Name, "config(")
+}
+
+// IsFile returns true if dependency is a file path
+func (d *Dependency) IsFile() bool {
+	return strings.HasPrefix(d.Name, "/")
+}
+
+// IsVirtual returns true if dependency is a plain virtual capability (i.e.
+// not a file, library, rich or namespaced capability)
+func (d *Dependency) IsVirtual() bool {
+	return !d.IsFile() && !d.IsRich() && !d.IsInternal() &&
+		!strings.ContainsAny(d.Name, "()")
+}
+
+// Matches returns true if given provided capability satisfies dependency
+func (d *Dependency) Matches(provide *Dependency) bool {
+	if d == nil || provide == nil || d.Name != provide.Name {
+		return false
+	}
+
+	if d.Op() == "" || d.Version == "" || provide.Op() == "" || provide.Version == "" {
+		return true
+	}
+
+	sense := CompareEVR(provide.Version, d.Version)
+
+	switch {
+	case sense < 0:
+		return provide.Flags&SENSE_GREATER != 0 || d.Flags&SENSE_LESS != 0
+	case sense > 0:
+		return provide.Flags&SENSE_LESS != 0 || d.Flags&SENSE_GREATER != 0
+	}
+
+	return (provide.Flags&SENSE_EQUAL != 0 && d.Flags&SENSE_EQUAL != 0) ||
+		(provide.Flags&SENSE_LESS != 0 && d.Flags&SENSE_LESS != 0) ||
+		(provide.Flags&SENSE_GREATER != 0 && d.Flags&SENSE_GREATER != 0)
+}
+
+// String returns string representation of dependency
+func (d *Dependency) String() string {
+	op := d.Op()
+
+	if op == "" || d.Version == "" {
+		return d.Name
+	}
+
+	return d.Name + " " + op + " " + d.Version
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// extractHeaderDeps extracts all dependencies from header
+func extractHeaderDeps(hdr *header) Dependencies {
+	var deps Dependencies
+
+	for _, tag := range depTags {
+		names := hdr.GetStrings(tag.name)
+		flags := hdr.GetInts(tag.flags)
+		versions := hdr.GetStrings(tag.version)
+
+		for i, name := range names {
+			dep := &Dependency{Name: name}
+
+			if i < len(flags) {
+				dep.Flags = flags[i]
+			}
+
+			if i < len(versions) {
+				dep.Version = versions[i]
+			}
+
+			deps.Add(tag.kind, dep)
+		}
+	}
+
+	return deps
+}
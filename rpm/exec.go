@@ -0,0 +1,302 @@
+package rpm
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/essentialkaos/ek/v13/env"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// dumpLineRegex is regular expression for parsing rpm --dump output
+var dumpLineRegex = regexp.MustCompile(
+	`^(.+?) (\d+) (-?\d+) ([0-9a-fA-F]*) (0?[0-7]+) (\S+) (\S+) ([01]) ([01]) (0x[0-9a-fA-F]+|\d+) (.*)$`,
+)
+
+// infoQueryFormat is query format for reading package metadata
+var infoQueryFormat = strings.Join([]string{
+	"%{name}", "%{epoch}", "%{version}", "%{release}", "%{arch}", "%{sourcerpm}",
+	"%{summary}", "%{url}", "%{license}", "%{vendor}", "%{buildtime}", "%{sourcepackage}",
+}, "\n") + "\n"
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ExecReader is package reader which uses rpm utility
+type ExecReader struct{}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Name returns name of reader
+func (r *ExecReader) Name() string {
+	return "rpm"
+}
+
+// IsSupported returns true if rpm utility is present on the system
+func (r *ExecReader) IsSupported() bool {
+	return env.Which("rpm") != ""
+}
+
+// IsPackage returns true if given file is an rpm package
+func (r *ExecReader) IsPackage(file string) bool {
+	_, err := execRPMCommand("-qp", file)
+	return err == nil
+}
+
+// ReadInfo reads package metadata
+func (r *ExecReader) ReadInfo(file string) (*Package, error) {
+	pkg, err := extractPackageInfo(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	pkg.Deps, err = extractDependencies(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pkg, nil
+}
+
+// Read reads full info about package
+func (r *ExecReader) Read(file string) (*Package, error) {
+	return readPackageExec(file)
+}
+
+// ReadFiles reads content of files with given paths from package payload
+func (r *ExecReader) ReadFiles(file string, paths []string, handler FileHandler) error {
+	return ReadFiles(file, paths, handler)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// readPackageExec reads info from package using rpm utility
+func readPackageExec(file string) (*Package, error) {
+	pkg, err := extractPackageInfo(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	pkg.Deps, err = extractDependencies(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	pkg.Payload, err = extractPayloadInfo(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	pkg.Scriptlets, err = extractScriptlets(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pkg, nil
+}
+
+// extractPayloadInfo extracts info about package payload
+func extractPayloadInfo(file string) ([]*Object, error) {
+	dumpData, err := execRPMCommand("-qp", "--dump", file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return parseDumpData(dumpData)
+}
+
+// extractScriptlets extracts scriptlets and triggers
+func extractScriptlets(file string) ([]*Scriptlet, error) {
+	data, err := execRPMCommand("-qp", "--scripts", "--triggers", file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return parseScriptlets(data), nil
+}
+
+// extractPackageInfo extracts package metadata
+func extractPackageInfo(file string) (*Package, error) {
+	data, err := execRPMCommand("-qp", "--qf", infoQueryFormat, file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	fields := strings.Split(strings.TrimRight(data, "\n"), "\n")
+
+	if len(fields) != 12 {
+		return nil, fmt.Errorf("Can't parse info about package %s", file)
+	}
+
+	for i := range fields {
+		if fields[i] == "(none)" {
+			fields[i] = ""
+		}
+	}
+
+	buildTime, _ := strconv.ParseInt(fields[10], 10, 64)
+
+	return &Package{
+		Name:      fields[0],
+		Epoch:     fields[1],
+		Version:   fields[2],
+		Release:   fields[3],
+		Arch:      fields[4],
+		Dist:      ExtractDist(fields[3]),
+		SourceRPM: fields[5],
+		Summary:   fields[6],
+		URL:       fields[7],
+		License:   fields[8],
+		Vendor:    fields[9],
+		BuildTime: time.Unix(buildTime, 0),
+		IsSrc:     fields[11] == "1",
+	}, nil
+}
+
+// extractDependencies extracts package dependencies
+func extractDependencies(file string) (Dependencies, error) {
+	var deps Dependencies
+
+	data, err := execRPMCommand("-qp", "--qf", genDepsQueryFormat(depTags[:4]), file)
+
+	if err != nil {
+		return deps, err
+	}
+
+	// Old versions of rpm don't support weak dependencies tags, so we
+	// ignore errors here
+	weakData, _ := execRPMCommand("-qp", "--qf", genDepsQueryFormat(depTags[4:]), file)
+
+	parseDependencies(data+weakData, &deps)
+
+	return deps, nil
+}
+
+// genDepsQueryFormat generates query format for reading dependencies
+func genDepsQueryFormat(tags []depTag) string {
+	var result string
+
+	for _, tag := range tags {
+		kind := strings.TrimSuffix(strings.ToUpper(tag.kind), "S")
+		result += fmt.Sprintf(
+			"[%s\t%%{%sNAME}\t%%{%sFLAGS}\t%%{%sVERSION}\n]",
+			tag.kind, kind, kind, kind,
+		)
+	}
+
+	return result
+}
+
+// parseDependencies parses dependencies data
+func parseDependencies(data string, deps *Dependencies) {
+	s := bufio.NewScanner(strings.NewReader(data))
+
+	for s.Scan() {
+		fields := strings.Split(s.Text(), "\t")
+
+		if len(fields) != 4 || fields[1] == "(none)" {
+			continue
+		}
+
+		flags, _ := strconv.ParseInt(fields[2], 10, 64)
+
+		deps.Add(fields[0], &Dependency{
+			Name:    fields[1],
+			Flags:   flags,
+			Version: fields[3],
+		})
+	}
+}
+
+// parseDumpData parses dump data
+func parseDumpData(data string) ([]*Object, error) {
+	r := strings.NewReader(data)
+	s := bufio.NewScanner(r)
+
+	var payload []*Object
+
+	for s.Scan() {
+		obj, err := parsePayloadInfo(s.Text())
+
+		if err != nil {
+			return nil, err
+		}
+
+		payload = append(payload, obj)
+	}
+
+	return payload, nil
+}
+
+// parsePayloadInfo parses payload object info. Dump line contains 11 fields:
+// path, size, mtime, digest, mode, owner, group, config flag, doc flag, rdev and
+// link target. Path and link target can contain spaces, so line is parsed using
+// regular expression.
+func parsePayloadInfo(data string) (*Object, error) {
+	fields := dumpLineRegex.FindStringSubmatch(data)
+
+	if fields == nil {
+		return nil, fmt.Errorf("Can't parse payload info %q", data)
+	}
+
+	size, _ := strconv.ParseInt(fields[2], 10, 64)
+	mtime, _ := strconv.ParseInt(fields[3], 10, 64)
+	mode, _ := strconv.ParseUint(fields[5], 8, 32)
+	rdev, _ := strconv.ParseUint(fields[10], 0, 64)
+
+	digest := fields[4]
+
+	// rpm prints zero-filled digest for objects without content
+	if strings.Trim(digest, "0") == "" {
+		digest = ""
+	}
+
+	obj := &Object{
+		Path:     fields[1],
+		User:     fields[6],
+		Group:    fields[7],
+		Digest:   digest,
+		Size:     size,
+		MTime:    time.Unix(mtime, 0),
+		Rdev:     rdev,
+		Mode:     os.FileMode(mode & 07777),
+		IsConfig: fields[8] == "1",
+		IsDoc:    fields[9] == "1",
+		IsDir:    mode&_S_IFMT == _S_IFDIR,
+		IsLink:   mode&_S_IFMT == _S_IFLNK,
+	}
+
+	if obj.IsLink {
+		obj.LinkTo = fields[11]
+	}
+
+	return obj, nil
+}
+
+// execRPMCommand executes rpm command with given options
+func execRPMCommand(options ...string) (string, error) {
+	output, err := exec.Command("rpm", options...).Output()
+	return string(output), err
+}
@@ -0,0 +1,454 @@
+package rpm
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Header tags
+const (
+	TAG_NAME              = 1000
+	TAG_VERSION           = 1001
+	TAG_RELEASE           = 1002
+	TAG_EPOCH             = 1003
+	TAG_SUMMARY           = 1004
+	TAG_BUILDTIME         = 1006
+	TAG_VENDOR            = 1011
+	TAG_LICENSE           = 1014
+	TAG_URL               = 1020
+	TAG_ARCH              = 1022
+	TAG_PREIN             = 1023
+	TAG_POSTIN            = 1024
+	TAG_PREUN             = 1025
+	TAG_POSTUN            = 1026
+	TAG_OLDFILENAMES      = 1027
+	TAG_FILESIZES         = 1028
+	TAG_FILEMODES         = 1030
+	TAG_FILERDEVS         = 1033
+	TAG_FILEMTIMES        = 1034
+	TAG_FILEDIGESTS       = 1035
+	TAG_FILELINKTOS       = 1036
+	TAG_FILEFLAGS         = 1037
+	TAG_FILEUSERNAME      = 1039
+	TAG_FILEGROUPNAME     = 1040
+	TAG_SOURCERPM         = 1044
+	TAG_PROVIDENAME       = 1047
+	TAG_REQUIREFLAGS      = 1048
+	TAG_REQUIRENAME       = 1049
+	TAG_REQUIREVERSION    = 1050
+	TAG_CONFLICTFLAGS     = 1053
+	TAG_CONFLICTNAME      = 1054
+	TAG_CONFLICTVERSION   = 1055
+	TAG_TRIGGERSCRIPTS    = 1065
+	TAG_TRIGGERNAME       = 1066
+	TAG_TRIGGERVERSION    = 1067
+	TAG_TRIGGERFLAGS      = 1068
+	TAG_TRIGGERINDEX      = 1069
+	TAG_PREINPROG         = 1085
+	TAG_POSTINPROG        = 1086
+	TAG_PREUNPROG         = 1087
+	TAG_POSTUNPROG        = 1088
+	TAG_OBSOLETENAME      = 1090
+	TAG_TRIGGERSCRIPTPROG = 1092
+	TAG_SOURCEPACKAGE     = 1106
+	TAG_PROVIDEFLAGS      = 1112
+	TAG_PROVIDEVERSION    = 1113
+	TAG_OBSOLETEFLAGS     = 1114
+	TAG_OBSOLETEVERSION   = 1115
+	TAG_DIRINDEXES        = 1116
+	TAG_BASENAMES         = 1117
+	TAG_DIRNAMES          = 1118
+	TAG_PAYLOADFORMAT     = 1124
+	TAG_PAYLOADCOMPRESSOR = 1125
+	TAG_PRETRANS          = 1151
+	TAG_POSTTRANS         = 1152
+	TAG_PRETRANSPROG      = 1153
+	TAG_POSTTRANSPROG     = 1154
+	TAG_LONGFILESIZES     = 5008
+	TAG_RECOMMENDNAME     = 5046
+	TAG_RECOMMENDVERSION  = 5047
+	TAG_RECOMMENDFLAGS    = 5048
+	TAG_SUGGESTNAME       = 5049
+	TAG_SUGGESTVERSION    = 5050
+	TAG_SUGGESTFLAGS      = 5051
+	TAG_SUPPLEMENTNAME    = 5052
+	TAG_SUPPLEMENTVERSION = 5053
+	TAG_SUPPLEMENTFLAGS   = 5054
+	TAG_ENHANCENAME       = 5055
+	TAG_ENHANCEVERSION    = 5056
+	TAG_ENHANCEFLAGS      = 5057
+	TAG_PREUNTRANS        = 5101
+	TAG_POSTUNTRANS       = 5102
+	TAG_PREUNTRANSPROG    = 5103
+	TAG_POSTUNTRANSPROG   = 5104
+)
+
+// Header entry types
+const (
+	TYPE_NULL         = 0
+	TYPE_CHAR         = 1
+	TYPE_INT8         = 2
+	TYPE_INT16        = 3
+	TYPE_INT32        = 4
+	TYPE_INT64        = 5
+	TYPE_STRING       = 6
+	TYPE_BIN          = 7
+	TYPE_STRING_ARRAY = 8
+	TYPE_I18NSTRING   = 9
+)
+
+// File flags
+const (
+	FILE_CONFIG = 1 << 0
+	FILE_DOC    = 1 << 1
+)
+
+// Dependency sense flags
+const (
+	SENSE_LESS          = 1 << 1
+	SENSE_GREATER       = 1 << 2
+	SENSE_EQUAL         = 1 << 3
+	SENSE_PREREQ        = 1 << 6
+	SENSE_SCRIPT_PRE    = 1 << 9
+	SENSE_SCRIPT_POST   = 1 << 10
+	SENSE_TRIGGERIN     = 1 << 16
+	SENSE_TRIGGERUN     = 1 << 17
+	SENSE_TRIGGERPOSTUN = 1 << 18
+	SENSE_RPMLIB        = 1 << 24
+	SENSE_TRIGGERPREIN  = 1 << 25
+)
+
+// File types
+const (
+	_S_IFMT  = 0170000
+	_S_IFDIR = 0040000
+	_S_IFREG = 0100000
+	_S_IFLNK = 0120000
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	// LEAD_SIZE is size of package lead
+	LEAD_SIZE = 96
+
+	// MAX_HEADER_ENTRIES is maximum number of entries in header
+	MAX_HEADER_ENTRIES = 0xFFFF
+
+	// MAX_HEADER_SIZE is maximum size of header data store
+	MAX_HEADER_SIZE = 256 * 1024 * 1024
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// lead contains info from package lead
+type lead struct {
+	Major uint8
+	Minor uint8
+	Type  uint16
+}
+
+// header contains raw header data
+type header struct {
+	entries map[int32]headerEntry
+	store   []byte
+}
+
+// headerEntry contains info about header index entry
+type headerEntry struct {
+	Type   int32
+	Offset int32
+	Count  int32
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	leadMagic   = []byte{0xED, 0xAB, 0xEE, 0xDB}
+	headerMagic = []byte{0x8E, 0xAD, 0xE8, 0x01}
+)
+
+var (
+	ErrNotPackage    = errors.New("File is not an rpm package")
+	ErrInvalidHeader = errors.New("Package header is malformed")
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// openPackage opens package file and reads lead, signature and header. The returned
+// file is positioned at the beginning of the payload.
+func openPackage(file string) (*os.File, *lead, *header, error) {
+	fd, err := os.Open(file)
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	ld, err := readLead(fd)
+
+	if err != nil {
+		fd.Close()
+		return nil, nil, nil, err
+	}
+
+	_, err = readHeader(fd, true)
+
+	if err != nil {
+		fd.Close()
+		return nil, nil, nil, fmt.Errorf("Can't read signature of %s: %w", file, err)
+	}
+
+	hdr, err := readHeader(fd, false)
+
+	if err != nil {
+		fd.Close()
+		return nil, nil, nil, fmt.Errorf("Can't read header of %s: %w", file, err)
+	}
+
+	return fd, ld, hdr, nil
+}
+
+// isPackageFile returns true if given file starts with rpm lead
+func isPackageFile(file string) bool {
+	fd, err := os.Open(file)
+
+	if err != nil {
+		return false
+	}
+
+	defer fd.Close()
+
+	_, err = readLead(fd)
+
+	return err == nil
+}
+
+// readLead reads package lead
+func readLead(r io.Reader) (*lead, error) {
+	buf := make([]byte, LEAD_SIZE)
+
+	_, err := io.ReadFull(r, buf)
+
+	if err != nil || !bytes.Equal(buf[:4], leadMagic) {
+		return nil, ErrNotPackage
+	}
+
+	return &lead{
+		Major: buf[4],
+		Minor: buf[5],
+		Type:  binary.BigEndian.Uint16(buf[6:8]),
+	}, nil
+}
+
+// readHeader reads header structure from reader. Signature header is padded to
+// 8-byte boundary, so for it alignment must be set to true.
+func readHeader(r io.Reader, align bool) (*header, error) {
+	intro := make([]byte, 16)
+
+	_, err := io.ReadFull(r, intro)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(intro[:4], headerMagic) {
+		return nil, ErrInvalidHeader
+	}
+
+	indexCount := binary.BigEndian.Uint32(intro[8:12])
+	storeSize := binary.BigEndian.Uint32(intro[12:16])
+
+	if indexCount > MAX_HEADER_ENTRIES || storeSize > MAX_HEADER_SIZE {
+		return nil, ErrInvalidHeader
+	}
+
+	index := make([]byte, indexCount*16)
+
+	_, err = io.ReadFull(r, index)
+
+	if err != nil {
+		return nil, err
+	}
+
+	dataSize := storeSize
+
+	if align && storeSize%8 != 0 {
+		dataSize += 8 - storeSize%8
+	}
+
+	store := make([]byte, dataSize)
+
+	_, err = io.ReadFull(r, store)
+
+	if err != nil {
+		return nil, err
+	}
+
+	hdr := &header{
+		entries: make(map[int32]headerEntry, indexCount),
+		store:   store[:storeSize],
+	}
+
+	for i := uint32(0); i < indexCount; i++ {
+		entry := index[i*16 : i*16+16]
+		tag := int32(binary.BigEndian.Uint32(entry[0:4]))
+
+		hdr.entries[tag] = headerEntry{
+			Type:   int32(binary.BigEndian.Uint32(entry[4:8])),
+			Offset: int32(binary.BigEndian.Uint32(entry[8:12])),
+			Count:  int32(binary.BigEndian.Uint32(entry[12:16])),
+		}
+	}
+
+	return hdr, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Has returns true if header contains given tag
+func (h *header) Has(tag int32) bool {
+	_, ok := h.entries[tag]
+	return ok
+}
+
+// GetString returns string value of given tag
+func (h *header) GetString(tag int32) string {
+	values := h.GetStrings(tag)
+
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+// GetStrings returns string array value of given tag
+func (h *header) GetStrings(tag int32) []string {
+	entry, ok := h.entries[tag]
+
+	if !ok || entry.Offset < 0 || int(entry.Offset) >= len(h.store) {
+		return nil
+	}
+
+	switch entry.Type {
+	case TYPE_STRING, TYPE_STRING_ARRAY, TYPE_I18NSTRING:
+	default:
+		return nil
+	}
+
+	count := int(entry.Count)
+
+	if entry.Type == TYPE_STRING {
+		count = 1
+	}
+
+	var result []string
+
+	data := h.store[entry.Offset:]
+
+	for i := 0; i < count; i++ {
+		end := bytes.IndexByte(data, 0)
+
+		if end == -1 {
+			break
+		}
+
+		result = append(result, string(data[:end]))
+		data = data[end+1:]
+	}
+
+	return result
+}
+
+// GetInt returns first integer value of given tag
+func (h *header) GetInt(tag int32) int64 {
+	values := h.GetInts(tag)
+
+	if len(values) == 0 {
+		return 0
+	}
+
+	return values[0]
+}
+
+// GetInts returns integer array value of given tag
+func (h *header) GetInts(tag int32) []int64 {
+	entry, ok := h.entries[tag]
+
+	if !ok || entry.Offset < 0 || entry.Count < 0 {
+		return nil
+	}
+
+	var size int
+
+	switch entry.Type {
+	case TYPE_CHAR, TYPE_INT8:
+		size = 1
+	case TYPE_INT16:
+		size = 2
+	case TYPE_INT32:
+		size = 4
+	case TYPE_INT64:
+		size = 8
+	default:
+		return nil
+	}
+
+	start := int(entry.Offset)
+	end := start + int(entry.Count)*size
+
+	if end > len(h.store) {
+		return nil
+	}
+
+	result := make([]int64, 0, entry.Count)
+
+	for i := start; i < end; i += size {
+		switch size {
+		case 1:
+			result = append(result, int64(h.store[i]))
+		case 2:
+			result = append(result, int64(binary.BigEndian.Uint16(h.store[i:])))
+		case 4:
+			result = append(result, int64(binary.BigEndian.Uint32(h.store[i:])))
+		case 8:
+			result = append(result, int64(binary.BigEndian.Uint64(h.store[i:])))
+		}
+	}
+
+	return result
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// formatSenseFlags formats comparison part of dependency sense flags
+func formatSenseFlags(flags int64) string {
+	var result string
+
+	if flags&SENSE_LESS != 0 {
+		result += "<"
+	}
+
+	if flags&SENSE_GREATER != 0 {
+		result += ">"
+	}
+
+	if flags&SENSE_EQUAL != 0 {
+		result += "="
+	}
+
+	return result
+}
@@ -0,0 +1,126 @@
+package rpm
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// MemoryReader is package reader which returns predefined packages. It can be
+// used for testing code which reads packages without real rpm files.
+type MemoryReader struct {
+	packages map[string]*Package
+	contents map[string]map[string][]byte
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewMemoryReader creates new in-memory reader
+func NewMemoryReader() *MemoryReader {
+	return &MemoryReader{
+		packages: make(map[string]*Package),
+		contents: make(map[string]map[string][]byte),
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Add adds package with given file name to reader
+func (r *MemoryReader) Add(file string, pkg *Package) *MemoryReader {
+	if r != nil && pkg != nil {
+		r.packages[file] = pkg
+	}
+
+	return r
+}
+
+// AddContent adds content of payload files for package with given file name
+func (r *MemoryReader) AddContent(file string, contents map[string][]byte) *MemoryReader {
+	if r == nil {
+		return r
+	}
+
+	if r.contents[file] == nil {
+		r.contents[file] = make(map[string][]byte)
+	}
+
+	for path, data := range contents {
+		r.contents[file][path] = data
+	}
+
+	return r
+}
+
+// Name returns name of reader
+func (r *MemoryReader) Name() string {
+	return "memory"
+}
+
+// IsSupported returns true if reader can be used on current system
+func (r *MemoryReader) IsSupported() bool {
+	return r != nil
+}
+
+// IsPackage returns true if reader contains package with given file name
+func (r *MemoryReader) IsPackage(file string) bool {
+	return r.packages[file] != nil
+}
+
+// ReadInfo reads package metadata
+func (r *MemoryReader) ReadInfo(file string) (*Package, error) {
+	pkg, err := r.Read(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	pkg.Scriptlets, pkg.Payload = nil, nil
+
+	return pkg, nil
+}
+
+// Read reads full info about package
+func (r *MemoryReader) Read(file string) (*Package, error) {
+	pkg := r.packages[file]
+
+	if pkg == nil {
+		return nil, fmt.Errorf("Package %s not found", file)
+	}
+
+	// Return copy, because caller can modify package info
+	result := *pkg
+
+	return &result, nil
+}
+
+// ReadFiles reads content of files with given paths from predefined content
+func (r *MemoryReader) ReadFiles(file string, paths []string, handler FileHandler) error {
+	if r.packages[file] == nil {
+		return fmt.Errorf("Package %s not found", file)
+	}
+
+	contents := r.contents[file]
+
+	for _, path := range paths {
+		data, ok := contents[path]
+
+		if !ok {
+			continue
+		}
+
+		err := handler(path, data)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
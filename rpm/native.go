@@ -0,0 +1,302 @@
+package rpm
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// scriptletTags contains tags with scriptlets in the order used by rpm
+var scriptletTags = []struct {
+	phase string
+	body  int32
+	prog  int32
+}{
+	{PHASE_PRETRANS, TAG_PRETRANS, TAG_PRETRANSPROG},
+	{PHASE_PRE, TAG_PREIN, TAG_PREINPROG},
+	{PHASE_POST, TAG_POSTIN, TAG_POSTINPROG},
+	{PHASE_PREUN, TAG_PREUN, TAG_PREUNPROG},
+	{PHASE_POSTUN, TAG_POSTUN, TAG_POSTUNPROG},
+	{PHASE_POSTTRANS, TAG_POSTTRANS, TAG_POSTTRANSPROG},
+	{PHASE_PREUNTRANS, TAG_PREUNTRANS, TAG_PREUNTRANSPROG},
+	{PHASE_POSTUNTRANS, TAG_POSTUNTRANS, TAG_POSTUNTRANSPROG},
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NativeReader is package reader which uses built-in header parser
+type NativeReader struct{}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Name returns name of reader
+func (r *NativeReader) Name() string {
+	return "native"
+}
+
+// IsSupported returns true if reader can be used on current system
+func (r *NativeReader) IsSupported() bool {
+	return true
+}
+
+// IsPackage returns true if given file is an rpm package
+func (r *NativeReader) IsPackage(file string) bool {
+	return isPackageFile(file)
+}
+
+// ReadInfo reads package metadata
+func (r *NativeReader) ReadInfo(file string) (*Package, error) {
+	return readPackageNative(file, false)
+}
+
+// Read reads full info about package
+func (r *NativeReader) Read(file string) (*Package, error) {
+	return readPackageNative(file, true)
+}
+
+// ReadFiles reads content of files with given paths from package payload
+func (r *NativeReader) ReadFiles(file string, paths []string, handler FileHandler) error {
+	return ReadFiles(file, paths, handler)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// readPackageNative reads package info using built-in header parser
+func readPackageNative(file string, full bool) (*Package, error) {
+	fd, ld, hdr, err := openPackage(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer fd.Close()
+
+	pkg := &Package{
+		Name:      hdr.GetString(TAG_NAME),
+		Version:   hdr.GetString(TAG_VERSION),
+		Release:   hdr.GetString(TAG_RELEASE),
+		Arch:      hdr.GetString(TAG_ARCH),
+		Dist:      ExtractDist(hdr.GetString(TAG_RELEASE)),
+		SourceRPM: hdr.GetString(TAG_SOURCERPM),
+		Summary:   hdr.GetString(TAG_SUMMARY),
+		URL:       hdr.GetString(TAG_URL),
+		License:   hdr.GetString(TAG_LICENSE),
+		Vendor:    hdr.GetString(TAG_VENDOR),
+		BuildTime: time.Unix(hdr.GetInt(TAG_BUILDTIME), 0),
+		IsSrc:     ld.Type == 1 || hdr.Has(TAG_SOURCEPACKAGE),
+	}
+
+	if hdr.Has(TAG_EPOCH) {
+		pkg.Epoch = strconv.FormatInt(hdr.GetInt(TAG_EPOCH), 10)
+	}
+
+	pkg.Deps = extractHeaderDeps(hdr)
+
+	if !full {
+		return pkg, nil
+	}
+
+	pkg.Scriptlets = extractHeaderScriptlets(hdr)
+	pkg.Payload, err = extractHeaderPayload(hdr)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't read payload info from %s: %w", file, err)
+	}
+
+	return pkg, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// extractHeaderPayload extracts info about payload objects from header
+func extractHeaderPayload(hdr *header) ([]*Object, error) {
+	paths := extractHeaderPaths(hdr)
+
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	modes := hdr.GetInts(TAG_FILEMODES)
+	flags := hdr.GetInts(TAG_FILEFLAGS)
+	users := hdr.GetStrings(TAG_FILEUSERNAME)
+	groups := hdr.GetStrings(TAG_FILEGROUPNAME)
+	sizes := hdr.GetInts(TAG_LONGFILESIZES)
+	mtimes := hdr.GetInts(TAG_FILEMTIMES)
+	digests := hdr.GetStrings(TAG_FILEDIGESTS)
+	links := hdr.GetStrings(TAG_FILELINKTOS)
+	rdevs := hdr.GetInts(TAG_FILERDEVS)
+
+	if len(sizes) == 0 {
+		sizes = hdr.GetInts(TAG_FILESIZES)
+	}
+
+	if len(modes) != len(paths) || len(users) != len(paths) || len(groups) != len(paths) {
+		return nil, ErrInvalidHeader
+	}
+
+	var payload []*Object
+
+	for i, path := range paths {
+		mode := modes[i]
+
+		obj := &Object{
+			Path:   path,
+			User:   users[i],
+			Group:  groups[i],
+			Mode:   os.FileMode(mode & 07777),
+			IsDir:  mode&_S_IFMT == _S_IFDIR,
+			IsLink: mode&_S_IFMT == _S_IFLNK,
+		}
+
+		if i < len(flags) {
+			obj.IsConfig = flags[i]&FILE_CONFIG != 0
+			obj.IsDoc = flags[i]&FILE_DOC != 0
+		}
+
+		if i < len(sizes) {
+			obj.Size = sizes[i]
+		}
+
+		if i < len(mtimes) {
+			obj.MTime = time.Unix(mtimes[i], 0)
+		}
+
+		if i < len(digests) {
+			obj.Digest = digests[i]
+		}
+
+		if i < len(links) {
+			obj.LinkTo = links[i]
+		}
+
+		if i < len(rdevs) {
+			obj.Rdev = uint64(rdevs[i])
+		}
+
+		payload = append(payload, obj)
+	}
+
+	return payload, nil
+}
+
+// extractHeaderPaths extracts full paths of payload objects from header
+func extractHeaderPaths(hdr *header) []string {
+	if !hdr.Has(TAG_BASENAMES) {
+		return hdr.GetStrings(TAG_OLDFILENAMES)
+	}
+
+	baseNames := hdr.GetStrings(TAG_BASENAMES)
+	dirNames := hdr.GetStrings(TAG_DIRNAMES)
+	dirIndexes := hdr.GetInts(TAG_DIRINDEXES)
+
+	if len(baseNames) != len(dirIndexes) {
+		return nil
+	}
+
+	var result []string
+
+	for i, baseName := range baseNames {
+		dirIndex := int(dirIndexes[i])
+
+		if dirIndex < 0 || dirIndex >= len(dirNames) {
+			return nil
+		}
+
+		result = append(result, dirNames[dirIndex]+baseName)
+	}
+
+	return result
+}
+
+// extractHeaderScriptlets extracts scriptlets and triggers from header
+func extractHeaderScriptlets(hdr *header) []*Scriptlet {
+	var result []*Scriptlet
+
+	for _, tag := range scriptletTags {
+		body := hdr.GetString(tag.body)
+		prog := hdr.GetStrings(tag.prog)
+
+		if body == "" && len(prog) == 0 {
+			continue
+		}
+
+		scriptlet := &Scriptlet{Phase: tag.phase, Body: body}
+
+		switch {
+		case len(prog) != 0:
+			scriptlet.Interpreter = strings.Join(prog, " ")
+		default:
+			scriptlet.Interpreter = "/bin/sh"
+		}
+
+		result = append(result, scriptlet)
+	}
+
+	return append(result, extractHeaderTriggers(hdr)...)
+}
+
+// extractHeaderTriggers extracts trigger scriptlets from header
+func extractHeaderTriggers(hdr *header) []*Scriptlet {
+	scripts := hdr.GetStrings(TAG_TRIGGERSCRIPTS)
+
+	if len(scripts) == 0 {
+		return nil
+	}
+
+	progs := hdr.GetStrings(TAG_TRIGGERSCRIPTPROG)
+	names := hdr.GetStrings(TAG_TRIGGERNAME)
+	versions := hdr.GetStrings(TAG_TRIGGERVERSION)
+	flags := hdr.GetInts(TAG_TRIGGERFLAGS)
+	indexes := hdr.GetInts(TAG_TRIGGERINDEX)
+
+	result := make([]*Scriptlet, len(scripts))
+
+	for i, script := range scripts {
+		result[i] = &Scriptlet{Body: script, Interpreter: "/bin/sh"}
+
+		if i < len(progs) && progs[i] != "" {
+			result[i].Interpreter = progs[i]
+		}
+	}
+
+	for i, name := range names {
+		if i >= len(indexes) || i >= len(flags) || int(indexes[i]) >= len(result) {
+			break
+		}
+
+		scriptlet := result[indexes[i]]
+
+		switch {
+		case flags[i]&SENSE_TRIGGERPREIN != 0:
+			scriptlet.Phase = PHASE_TRIGGERPREIN
+		case flags[i]&SENSE_TRIGGERUN != 0:
+			scriptlet.Phase = PHASE_TRIGGERUN
+		case flags[i]&SENSE_TRIGGERPOSTUN != 0:
+			scriptlet.Phase = PHASE_TRIGGERPOSTUN
+		default:
+			scriptlet.Phase = PHASE_TRIGGERIN
+		}
+
+		cond := name
+		sense := formatSenseFlags(flags[i])
+
+		if sense != "" && i < len(versions) && versions[i] != "" {
+			cond += " " + sense + " " + versions[i]
+		}
+
+		scriptlet.Triggers = append(scriptlet.Triggers, cond)
+	}
+
+	return result
+}
@@ -0,0 +1,317 @@
+package rpm
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// CPIO_HEADER_SIZE is size of cpio header in "newc" format
+const CPIO_HEADER_SIZE = 110
+
+// MAX_CPIO_NAME_SIZE is maximum size of file name in cpio archive
+const MAX_CPIO_NAME_SIZE = 4096
+
+const (
+	cpioMagicNewc     = "070701"
+	cpioMagicCRC      = "070702"
+	cpioMagicStripped = "07070X"
+	cpioTrailer       = "TRAILER!!!"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// PayloadFile contains info about file in package payload
+type PayloadFile struct {
+	Path  string
+	Mode  os.FileMode
+	Size  int64
+	Inode int64
+	Links int
+}
+
+// PayloadReader is reader for package payload (cpio archive)
+type PayloadReader struct {
+	fd     *os.File
+	dec    io.ReadCloser
+	r      *bufio.Reader
+	remain int64
+	pad    int64
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	ErrUnsupportedPayload = errors.New("Payload format is not supported")
+	ErrInvalidPayload     = errors.New("Payload archive is malformed")
+	ErrNoContent          = errors.New("Package content is not available")
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// OpenPayload opens payload of given package for reading
+func OpenPayload(file string) (*PayloadReader, error) {
+	fd, _, _, err := openPackage(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	dec, err := NewDecompressor(fd)
+
+	if err != nil {
+		fd.Close()
+		return nil, fmt.Errorf("Can't decompress payload of %s: %w", file, err)
+	}
+
+	return &PayloadReader{fd: fd, dec: dec, r: bufio.NewReader(dec)}, nil
+}
+
+// ReadFiles reads content of files with given paths from package payload and
+// passes it to handler
+func ReadFiles(file string, paths []string, handler FileHandler) error {
+	p, err := OpenPayload(file)
+
+	if err != nil {
+		return err
+	}
+
+	defer p.Close()
+
+	err = p.ReadFiles(paths, handler)
+
+	if err != nil {
+		return fmt.Errorf("Can't read payload of %s: %w", file, err)
+	}
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Next moves reader to the next file in archive. Returns io.EOF at the end of
+// archive.
+func (p *PayloadReader) Next() (*PayloadFile, error) {
+	if p == nil || p.r == nil {
+		return nil, io.EOF
+	}
+
+	_, err := p.r.Discard(int(p.remain + p.pad))
+
+	if err != nil {
+		return nil, err
+	}
+
+	p.remain, p.pad = 0, 0
+
+	hdr := make([]byte, CPIO_HEADER_SIZE)
+	_, err = io.ReadFull(p.r, hdr)
+
+	if err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, ErrInvalidPayload
+		}
+
+		return nil, err
+	}
+
+	switch string(hdr[:6]) {
+	case cpioMagicNewc, cpioMagicCRC:
+		// ok
+	case cpioMagicStripped:
+		return nil, ErrUnsupportedPayload
+	default:
+		return nil, ErrInvalidPayload
+	}
+
+	var fields [13]int64
+
+	for i := range fields {
+		fields[i], err = strconv.ParseInt(string(hdr[6+i*8:14+i*8]), 16, 64)
+
+		if err != nil {
+			return nil, ErrInvalidPayload
+		}
+	}
+
+	nameSize := fields[11]
+
+	if nameSize <= 0 || nameSize > MAX_CPIO_NAME_SIZE {
+		return nil, ErrInvalidPayload
+	}
+
+	name := make([]byte, nameSize)
+	_, err = io.ReadFull(p.r, name)
+
+	if err != nil {
+		return nil, ErrInvalidPayload
+	}
+
+	_, err = p.r.Discard(int(cpioPadding(CPIO_HEADER_SIZE + nameSize)))
+
+	if err != nil {
+		return nil, ErrInvalidPayload
+	}
+
+	path := strings.TrimRight(string(name), "\x00")
+
+	if path == cpioTrailer {
+		return nil, io.EOF
+	}
+
+	f := &PayloadFile{
+		Path:  "/" + strings.TrimPrefix(strings.TrimPrefix(path, "."), "/"),
+		Mode:  fileModeFromUnix(fields[1]),
+		Size:  fields[6],
+		Inode: fields[0],
+		Links: int(fields[4]),
+	}
+
+	p.remain = f.Size
+	p.pad = cpioPadding(f.Size)
+
+	return f, nil
+}
+
+// ReadFiles reads content of files with given paths from the rest of payload
+// and passes it to handler
+func (p *PayloadReader) ReadFiles(paths []string, handler FileHandler) error {
+	wanted := make(map[string]bool)
+
+	for _, path := range paths {
+		wanted[path] = true
+	}
+
+	// Hardlinked files in cpio have data only in the last entry, so paths of
+	// wanted links are collected until all entries of the file are found
+	links := make(map[int64][]string)
+	seen := make(map[int64]int)
+
+	for len(wanted) != 0 {
+		f, err := p.Next()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		var targets []string
+
+		switch {
+		case f.Links > 1 && f.Mode.IsRegular():
+			if wanted[f.Path] {
+				links[f.Inode] = append(links[f.Inode], f.Path)
+			}
+
+			seen[f.Inode]++
+
+			if seen[f.Inode] < f.Links {
+				continue
+			}
+
+			targets = links[f.Inode]
+			delete(links, f.Inode)
+
+		case wanted[f.Path]:
+			targets = []string{f.Path}
+		}
+
+		if len(targets) == 0 {
+			continue
+		}
+
+		data := []byte{}
+
+		if f.Size != 0 && f.Mode.IsRegular() {
+			data, err = io.ReadAll(p)
+
+			if err != nil {
+				return fmt.Errorf("Can't read %s: %w", f.Path, err)
+			}
+		}
+
+		for _, path := range targets {
+			delete(wanted, path)
+
+			err = handler(path, data)
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Read reads data of current file
+func (p *PayloadReader) Read(b []byte) (int, error) {
+	if p.remain <= 0 {
+		return 0, io.EOF
+	}
+
+	if int64(len(b)) > p.remain {
+		b = b[:p.remain]
+	}
+
+	n, err := p.r.Read(b)
+	p.remain -= int64(n)
+
+	if err == io.EOF && p.remain > 0 {
+		err = io.ErrUnexpectedEOF
+	}
+
+	return n, err
+}
+
+// Close closes payload reader
+func (p *PayloadReader) Close() error {
+	if p == nil || p.fd == nil {
+		return nil
+	}
+
+	p.dec.Close()
+
+	return p.fd.Close()
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// cpioPadding returns size of padding for given size
+func cpioPadding(size int64) int64 {
+	return (4 - size%4) % 4
+}
+
+// fileModeFromUnix converts unix mode to os.FileMode
+func fileModeFromUnix(mode int64) os.FileMode {
+	result := os.FileMode(mode & 07777)
+
+	switch mode & _S_IFMT {
+	case _S_IFDIR:
+		result |= os.ModeDir
+	case _S_IFLNK:
+		result |= os.ModeSymlink
+	case _S_IFREG:
+		// regular file
+	default:
+		result |= os.ModeIrregular
+	}
+
+	return result
+}
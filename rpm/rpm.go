@@ -8,25 +8,62 @@ package rpm
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 import (
-	"bufio"
 	"fmt"
 	"os"
-	"os/exec"
-	"strconv"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/essentialkaos/ek/v13/strutil"
 )
 
 // ////////////////////////////////////////////////////////////////////////////////// //
 
+// PackageReader is interface for package reading backend
+type PackageReader interface {
+	// Name returns name of reader
+	Name() string
+
+	// IsSupported returns true if reader can be used on current system
+	IsSupported() bool
+
+	// IsPackage returns true if given file is a package which reader can read
+	IsPackage(file string) bool
+
+	// ReadInfo reads package metadata without info about payload and scriptlets
+	ReadInfo(file string) (*Package, error)
+
+	// Read reads full info about package
+	Read(file string) (*Package, error)
+
+	// ReadFiles reads content of files with given paths from package payload
+	ReadFiles(file string, paths []string, handler FileHandler) error
+}
+
+// FileHandler is function for handling content of payload file
+type FileHandler func(path string, data []byte) error
+
 // Package contains package info
 type Package struct {
+	File       string
 	Name       string
+	Epoch      string
+	Version    string
+	Release    string
+	Arch       string
 	Dist       string
-	Scriptlets string
+	SourceRPM  string
+	Summary    string
+	URL        string
+	License    string
+	Vendor     string
+	BuildTime  time.Time
+	Deps       Dependencies
+	Scriptlets []*Scriptlet
 	Payload    []*Object
 	IsSrc      bool
+
+	reader PackageReader
 }
 
 // Object contains info about payload object
@@ -34,54 +71,166 @@ type Object struct {
 	Path     string
 	User     string
 	Group    string
+	Digest   string
+	LinkTo   string
+	MTime    time.Time
+	Size     int64
+	Rdev     uint64
 	Mode     os.FileMode
 	IsConfig bool
+	IsDoc    bool
 	IsDir    bool
 	IsLink   bool
+	NoMeta   bool // Mode, owner, digest and link target are unknown
 }
 
 // ////////////////////////////////////////////////////////////////////////////////// //
 
-// ReadRPM reads info from package
+// readers is registry of package readers
+var readers = []PackageReader{&NativeReader{}, &ExecReader{}}
+
+// distTagRegex is regex for searching dist tag in release
+var distTagRegex = regexp.MustCompile(`(?:^|[.+])((?:el|fc|amzn|lp|sle)[0-9]+(?:_[0-9]+)*)(?:[.+~^]|$)`)
+
+// suseReleaseRegex is regex for SUSE release with encoded OS version
+// (e.g. 150500.3.3 → SLE 15 SP5)
+var suseReleaseRegex = regexp.MustCompile(`^(1[1-6])[0-9]{4}\.`)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ReadRPM reads info from package using the first registered reader which can
+// handle it. If reader fails, the next suitable reader is used as a fallback.
 func ReadRPM(file string) (*Package, error) {
-	var err error
+	return readWith(file, PackageReader.Read)
+}
+
+// ReadInfo reads package metadata (name, dist, source package flag) without info
+// about payload and scriptlets
+func ReadInfo(file string) (*Package, error) {
+	return readWith(file, PackageReader.ReadInfo)
+}
+
+// IsPackage returns true if given file is an rpm package
+func IsPackage(file string) bool {
+	return GetReader(file) != nil
+}
 
-	pkg := &Package{}
+// GetReader returns the first registered reader which can read given file
+func GetReader(file string) PackageReader {
+	for _, r := range readers {
+		if r.IsSupported() && r.IsPackage(file) {
+			return r
+		}
+	}
+
+	return nil
+}
 
-	pkg.Name, pkg.Dist, pkg.IsSrc, err = extractPackageInfo(file)
+// RegisterReader registers package reader. Registered readers take precedence
+// over already registered ones.
+func RegisterReader(r PackageReader) {
+	if r != nil {
+		readers = append([]PackageReader{r}, readers...)
+	}
+}
 
-	if err != nil {
-		return nil, err
+// RegisterFallbackReader registers package reader which will be used only if
+// none of already registered readers can read package
+func RegisterFallbackReader(r PackageReader) {
+	if r != nil {
+		readers = append(readers, r)
 	}
+}
+
+// SetReaders replaces all registered readers with given ones
+func SetReaders(r ...PackageReader) {
+	readers = r
+}
 
-	pkg.Payload, err = extractPayloadInfo(file)
+// ExtractDist extracts dist tag from release info
+// (e.g. "1.el8_9" → "el8_9", "1.module+el8.9.0+20000+abcd1234" → "el8")
+func ExtractDist(data string) string {
+	match := distTagRegex.FindStringSubmatch(data)
 
-	if err != nil {
-		return nil, err
+	if match != nil {
+		return match[1]
 	}
 
-	pkg.Scriptlets, err = extractScriptlets(file)
+	match = suseReleaseRegex.FindStringSubmatch(data)
 
-	if err != nil {
-		return nil, err
+	if match != nil {
+		return "sle" + match[1]
 	}
 
-	return pkg, nil
+	dotIndex := strings.LastIndex(data, ".")
+
+	return strutil.Substring(data, dotIndex+1, 9999)
 }
 
-// IsPackage returns true if given file is an rpm package
-func IsPackage(file string) bool {
-	_, err := execRPMCommand("-qp", file)
-	return err == nil
+// ParseSourceName extracts package name from source package file name
+// (e.g. "zlib-1.2.11-40.el9.src.rpm" → "zlib")
+func ParseSourceName(srpm string) string {
+	name := strings.TrimSuffix(srpm, ".rpm")
+	name = strings.TrimSuffix(name, ".src")
+	name = strings.TrimSuffix(name, ".nosrc")
+
+	for i := 0; i < 2; i++ {
+		dashIndex := strings.LastIndex(name, "-")
+
+		if dashIndex == -1 {
+			return name
+		}
+
+		name = name[:dashIndex]
+	}
+
+	return name
 }
 
 // ////////////////////////////////////////////////////////////////////////////////// //
 
+// EVR returns package epoch, version and release in rpm format
+func (p *Package) EVR() string {
+	if p.Epoch == "" {
+		return p.Version + "-" + p.Release
+	}
+
+	return p.Epoch + ":" + p.Version + "-" + p.Release
+}
+
+// NEVRA returns full package name with epoch, version, release and arch
+func (p *Package) NEVRA() string {
+	if p.Arch == "" {
+		return p.Name + "-" + p.EVR()
+	}
+
+	return p.Name + "-" + p.EVR() + "." + p.Arch
+}
+
+// SourceName returns name of source package
+func (p *Package) SourceName() string {
+	if p.IsSrc {
+		return p.Name
+	}
+
+	return ParseSourceName(p.SourceRPM)
+}
+
+// ReadFiles reads content of files with given paths from package payload using
+// reader which was used for reading package info
+func (p *Package) ReadFiles(paths []string, handler FileHandler) error {
+	if p == nil || p.reader == nil {
+		return ErrNoContent
+	}
+
+	return p.reader.ReadFiles(p.File, paths, handler)
+}
+
 // String returns string representation of package
 func (p *Package) String() string {
 	return fmt.Sprintf(
-		"{Name: %s | Dist: %s | Payload: %d | Scriplets: %d}",
-		p.Name, p.Dist, len(p.Payload), len(p.Scriptlets),
+		"{Name: %s | EVR: %s | Arch: %s | Dist: %s | Source: %s | Payload: %d | Scriplets: %d}",
+		p.Name, p.EVR(), p.Arch, p.Dist, p.SourceRPM, len(p.Payload), len(p.Scriptlets),
 	)
 }
 
@@ -90,6 +239,7 @@ func (o *Object) String() string {
 	user := o.User
 	group := o.Group
 	isConfig := "N"
+	isDoc := "N"
 	isDir := "N"
 	isLink := "N"
 
@@ -105,6 +255,10 @@ func (o *Object) String() string {
 		isConfig = "Y"
 	}
 
+	if o.IsDoc {
+		isDoc = "Y"
+	}
+
 	if o.IsDir {
 		isDir = "Y"
 	}
@@ -114,84 +268,34 @@ func (o *Object) String() string {
 	}
 
 	return fmt.Sprintf(
-		"{Path: %s | Mode: %s | User: %s | Group: %s | Config: %s | Dir: %s | Link: %s}",
-		o.Path, o.Mode, user, group, isConfig, isDir, isLink,
+		"{Path: %s | Size: %d | Mode: %s | User: %s | Group: %s | Config: %s | Doc: %s | Dir: %s | Link: %s}",
+		o.Path, o.Size, o.Mode, user, group, isConfig, isDoc, isDir, isLink,
 	)
 }
 
 // ////////////////////////////////////////////////////////////////////////////////// //
 
-// extractPayloadInfo extracts info about package payload
-func extractPayloadInfo(file string) ([]*Object, error) {
-	dumpData, err := execRPMCommand("-qp", "--dump", file)
-
-	if err != nil {
-		return nil, err
-	}
-
-	return parseDumpData(dumpData)
-}
-
-// extractScriptlets extracts raw scriptlets data
-func extractScriptlets(file string) (string, error) {
-	return execRPMCommand("-qp", "--scripts", file)
-}
-
-// extractPackageInfo extracts package name, dist and src package flag
-func extractPackageInfo(file string) (string, string, bool, error) {
-	data, err := execRPMCommand("-qp", "--qf", "%{name} %{release} %{sourcepackage}", file)
-
-	if err != nil {
-		return "", "", false, err
-	}
-
-	name := strutil.ReadField(data, 0, false, ' ')
-	dist := extractDist(strutil.ReadField(data, 1, false, ' '))
-	isSrc := strutil.ReadField(data, 2, false, ' ') == "1"
-
-	return name, dist, isSrc, nil
-}
+// readWith reads package using given reader method
+func readWith(file string, readFunc func(PackageReader, string) (*Package, error)) (*Package, error) {
+	var err error
+	var pkg *Package
 
-// parseDumpData parses dump data
-func parseDumpData(data string) ([]*Object, error) {
-	r := strings.NewReader(data)
-	s := bufio.NewScanner(r)
+	for _, r := range readers {
+		if !r.IsSupported() || !r.IsPackage(file) {
+			continue
+		}
 
-	var payload []*Object
+		pkg, err = readFunc(r, file)
 
-	for s.Scan() {
-		payload = append(payload, parsePayloadInfo(s.Text()))
+		if err == nil {
+			pkg.File, pkg.reader = file, r
+			return pkg, nil
+		}
 	}
 
-	return payload, nil
-}
-
-// parsePayloadInfo parses payload object info
-func parsePayloadInfo(data string) *Object {
-	modeStr := strutil.Tail(strutil.ReadField(data, 4, false, ' '), 4)
-	modeUint, _ := strconv.ParseUint(modeStr, 8, 32)
-	emptyHash := strings.Trim(strutil.ReadField(data, 3, false, ' '), "0") == ""
-	link := strutil.ReadField(data, 10, false, ' ')
-
-	return &Object{
-		Path:     strutil.ReadField(data, 0, false, ' '),
-		User:     strutil.ReadField(data, 5, false, ' '),
-		Group:    strutil.ReadField(data, 6, false, ' '),
-		Mode:     os.FileMode(modeUint),
-		IsConfig: strutil.ReadField(data, 7, false, ' ') == "1",
-		IsDir:    link == "X" && emptyHash,
-		IsLink:   link != "X" && emptyHash,
+	if err == nil {
+		err = fmt.Errorf("%s is not an rpm package", file)
 	}
-}
 
-// execRPMCommand executes rpm command with given options
-func execRPMCommand(options ...string) (string, error) {
-	output, err := exec.Command("rpm", options...).Output()
-	return string(output), err
-}
-
-// extractDist extracts dist data from release info
-func extractDist(data string) string {
-	dotIndex := strings.LastIndex(data, ".")
-	return strutil.Substring(data, dotIndex+1, 9999)
+	return nil, err
 }
@@ -0,0 +1,795 @@
+package rpm
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/essentialkaos/check"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func Test(t *testing.T) { TestingT(t) }
+
+type RPMSuite struct{}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var _ = Suite(&RPMSuite{})
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (s *RPMSuite) TestMemoryReader(c *C) {
+	pkg := &Package{Name: "app", Version: "1.0", Release: "1.el9", Payload: []*Object{{Path: "/etc/app.conf"}}}
+	reader := NewMemoryReader().
+		Add("app.rpm", pkg).
+		AddContent("app.rpm", map[string][]byte{"/etc/app.conf": []byte("port 80\n")})
+
+	SetReaders(reader)
+
+	p1, err := ReadRPM("app.rpm")
+
+	c.Assert(err, IsNil)
+	c.Assert(p1, Not(Equals), pkg)
+	c.Assert(p1.File, Equals, "app.rpm")
+	c.Assert(p1.Payload, HasLen, 1)
+	c.Assert(pkg.File, Equals, "")
+
+	p2, err := ReadInfo("app.rpm")
+
+	c.Assert(err, IsNil)
+	c.Assert(p2.Payload, IsNil)
+	c.Assert(pkg.Payload, HasLen, 1)
+
+	contents := make(map[string]string)
+
+	err = p1.ReadFiles([]string{"/etc/app.conf", "/etc/unknown.conf"}, func(path string, data []byte) error {
+		contents[path] = string(data)
+		return nil
+	})
+
+	c.Assert(err, IsNil)
+	c.Assert(contents, DeepEquals, map[string]string{"/etc/app.conf": "port 80\n"})
+
+	_, err = ReadRPM("unknown.rpm")
+	c.Assert(err, NotNil)
+
+	c.Assert((&Package{}).ReadFiles(nil, nil), Equals, ErrNoContent)
+}
+
+func (s *RPMSuite) TestPayloadReadFiles(c *C) {
+	payload := genTestCPIO([]testCPIOEntry{
+		{"./etc/app.conf", 1, 0100644, 1, "port 80\n"},
+		{"./usr/bin/app", 2, 0100755, 1, "ELF"},
+		{"./usr/bin/app-link", 3, 0120777, 1, "app"},
+		// Hardlinks with data in the last entry
+		{"./usr/bin/foo", 4, 0100755, 2, ""},
+		{"./usr/bin/foo-bar", 4, 0100755, 2, "FOO"},
+		// Empty hardlinked files
+		{"./etc/empty1", 5, 0100644, 2, ""},
+		{"./etc/empty2", 5, 0100644, 2, ""},
+	})
+
+	p := &PayloadReader{r: bufio.NewReader(bytes.NewReader(payload))}
+	contents := make(map[string]string)
+
+	err := p.ReadFiles(
+		[]string{"/etc/app.conf", "/usr/bin/app-link", "/usr/bin/foo", "/etc/empty1", "/etc/unknown"},
+		func(path string, data []byte) error {
+			contents[path] = string(data)
+			return nil
+		},
+	)
+
+	c.Assert(err, IsNil)
+	c.Assert(contents, DeepEquals, map[string]string{
+		"/etc/app.conf":     "port 80\n",
+		"/usr/bin/app-link": "",
+		"/usr/bin/foo":      "FOO",
+		"/etc/empty1":       "",
+	})
+
+	p = &PayloadReader{r: bufio.NewReader(bytes.NewReader(payload))}
+
+	err = p.ReadFiles([]string{"/usr/bin/app"}, func(path string, data []byte) error {
+		return fmt.Errorf("Handler error")
+	})
+
+	c.Assert(err, ErrorMatches, "Handler error")
+}
+
+func (s *RPMSuite) TestHeaderTags(c *C) {
+	data := genTestHeader([]testHeaderEntry{
+		{TAG_NAME, TYPE_STRING, "app"},
+		{TAG_SUMMARY, TYPE_I18NSTRING, []string{"Test app", "Тестовое приложение"}},
+		{TAG_DIRNAMES, TYPE_STRING_ARRAY, []string{"/etc/", "/usr/bin/"}},
+		{TAG_EPOCH, TYPE_INT32, []int32{2}},
+		{TAG_FILEMODES, TYPE_INT16, []uint16{0100644, 040755}},
+		{TAG_LONGFILESIZES, TYPE_INT64, []int64{1 << 33}},
+	}, false)
+
+	hdr, err := readHeader(bytes.NewReader(data), false)
+
+	c.Assert(err, IsNil)
+	c.Assert(hdr.Has(TAG_NAME), Equals, true)
+	c.Assert(hdr.Has(TAG_URL), Equals, false)
+	c.Assert(hdr.GetString(TAG_NAME), Equals, "app")
+	c.Assert(hdr.GetStrings(TAG_NAME), DeepEquals, []string{"app"})
+	c.Assert(hdr.GetString(TAG_SUMMARY), Equals, "Test app")
+	c.Assert(hdr.GetStrings(TAG_DIRNAMES), DeepEquals, []string{"/etc/", "/usr/bin/"})
+	c.Assert(hdr.GetString(TAG_URL), Equals, "")
+	c.Assert(hdr.GetInt(TAG_EPOCH), Equals, int64(2))
+	c.Assert(hdr.GetInts(TAG_FILEMODES), DeepEquals, []int64{0100644, 040755})
+	c.Assert(hdr.GetInts(TAG_LONGFILESIZES), DeepEquals, []int64{1 << 33})
+	c.Assert(hdr.GetInt(TAG_URL), Equals, int64(0))
+
+	// Values with wrong type are ignored
+	c.Assert(hdr.GetStrings(TAG_EPOCH), IsNil)
+	c.Assert(hdr.GetInts(TAG_NAME), IsNil)
+}
+
+func (s *RPMSuite) TestHeaderAlignment(c *C) {
+	// Store of signature header is 5 bytes long, so it's padded with 3 bytes
+	sig := genTestHeader([]testHeaderEntry{{1000, TYPE_STRING, "abcd"}}, true)
+	hdr := genTestHeader([]testHeaderEntry{{TAG_NAME, TYPE_STRING, "app"}}, false)
+
+	c.Assert(len(sig)%8, Equals, 0)
+
+	r := bytes.NewReader(append(sig, hdr...))
+
+	_, err := readHeader(r, true)
+	c.Assert(err, IsNil)
+
+	h, err := readHeader(r, false)
+	c.Assert(err, IsNil)
+	c.Assert(h.GetString(TAG_NAME), Equals, "app")
+
+	r = bytes.NewReader(append(sig, hdr...))
+
+	_, err = readHeader(r, false)
+	c.Assert(err, IsNil)
+
+	_, err = readHeader(r, false)
+	c.Assert(err, Equals, ErrInvalidHeader)
+}
+
+func (s *RPMSuite) TestHeaderErrors(c *C) {
+	data := genTestHeader([]testHeaderEntry{{TAG_NAME, TYPE_STRING, "app"}}, false)
+	data[0] = 0x00
+
+	_, err := readHeader(bytes.NewReader(data), false)
+	c.Assert(err, Equals, ErrInvalidHeader)
+
+	_, err = readHeader(bytes.NewReader(genTestHeaderIntro(MAX_HEADER_ENTRIES+1, 16)), false)
+	c.Assert(err, Equals, ErrInvalidHeader)
+
+	_, err = readHeader(bytes.NewReader(genTestHeaderIntro(1, MAX_HEADER_SIZE+1)), false)
+	c.Assert(err, Equals, ErrInvalidHeader)
+
+	// Header is truncated
+	_, err = readHeader(bytes.NewReader(genTestHeaderIntro(MAX_HEADER_ENTRIES, MAX_HEADER_SIZE)), false)
+	c.Assert(err, NotNil)
+
+	_, err = readHeader(bytes.NewReader(nil), false)
+	c.Assert(err, NotNil)
+
+	_, err = readLead(bytes.NewReader(make([]byte, LEAD_SIZE)))
+	c.Assert(err, Equals, ErrNotPackage)
+
+	_, err = readLead(bytes.NewReader(leadMagic))
+	c.Assert(err, Equals, ErrNotPackage)
+}
+
+func (s *RPMSuite) TestHeaderPayload(c *C) {
+	entries := []testHeaderEntry{
+		{TAG_BASENAMES, TYPE_STRING_ARRAY, []string{"app.conf", "app", "app-link", "app"}},
+		{TAG_DIRNAMES, TYPE_STRING_ARRAY, []string{"/etc/", "/usr/bin/", "/usr/share/"}},
+		{TAG_DIRINDEXES, TYPE_INT32, []int32{0, 1, 1, 2}},
+		{TAG_FILEMODES, TYPE_INT16, []uint16{0100640, 0100755, 0120777, 040755}},
+		{TAG_FILEFLAGS, TYPE_INT32, []int32{FILE_CONFIG, 0, 0, FILE_DOC}},
+		{TAG_FILEUSERNAME, TYPE_STRING_ARRAY, []string{"root", "root", "root", "root"}},
+		{TAG_FILEGROUPNAME, TYPE_STRING_ARRAY, []string{"app", "root", "root", "root"}},
+		{TAG_FILESIZES, TYPE_INT32, []int32{12, 1024, 3, 4096}},
+		{TAG_FILELINKTOS, TYPE_STRING_ARRAY, []string{"", "", "app", ""}},
+	}
+
+	hdr, err := readHeader(bytes.NewReader(genTestHeader(entries, false)), false)
+	c.Assert(err, IsNil)
+
+	payload, err := extractHeaderPayload(hdr)
+
+	c.Assert(err, IsNil)
+	c.Assert(payload, HasLen, 4)
+
+	c.Assert(payload[0].Path, Equals, "/etc/app.conf")
+	c.Assert(payload[0].Mode, Equals, os.FileMode(0640))
+	c.Assert(payload[0].Group, Equals, "app")
+	c.Assert(payload[0].Size, Equals, int64(12))
+	c.Assert(payload[0].IsConfig, Equals, true)
+	c.Assert(payload[0].IsDir, Equals, false)
+	c.Assert(payload[0].IsLink, Equals, false)
+
+	c.Assert(payload[1].Path, Equals, "/usr/bin/app")
+	c.Assert(payload[1].Mode, Equals, os.FileMode(0755))
+	c.Assert(payload[1].IsConfig, Equals, false)
+
+	c.Assert(payload[2].Path, Equals, "/usr/bin/app-link")
+	c.Assert(payload[2].IsLink, Equals, true)
+	c.Assert(payload[2].IsDir, Equals, false)
+	c.Assert(payload[2].LinkTo, Equals, "app")
+
+	c.Assert(payload[3].Path, Equals, "/usr/share/app")
+	c.Assert(payload[3].IsDir, Equals, true)
+	c.Assert(payload[3].IsLink, Equals, false)
+	c.Assert(payload[3].IsDoc, Equals, true)
+
+	// Number of owners doesn't match number of files
+	entries[5] = testHeaderEntry{TAG_FILEUSERNAME, TYPE_STRING_ARRAY, []string{"root"}}
+	hdr, err = readHeader(bytes.NewReader(genTestHeader(entries, false)), false)
+	c.Assert(err, IsNil)
+
+	_, err = extractHeaderPayload(hdr)
+	c.Assert(err, Equals, ErrInvalidHeader)
+}
+
+func (s *RPMSuite) TestHeaderScriptlets(c *C) {
+	data := genTestHeader([]testHeaderEntry{
+		{TAG_PREIN, TYPE_STRING, "useradd -r app"},
+		{TAG_POSTIN, TYPE_STRING, "print(\"ok\")"},
+		{TAG_POSTINPROG, TYPE_STRING_ARRAY, []string{"<lua>"}},
+		{TAG_POSTUN, TYPE_STRING, "systemctl daemon-reload"},
+		{TAG_POSTUNPROG, TYPE_STRING_ARRAY, []string{"/bin/sh", "-e"}},
+		{TAG_POSTTRANSPROG, TYPE_STRING_ARRAY, []string{"/sbin/ldconfig"}},
+		{TAG_TRIGGERSCRIPTS, TYPE_STRING_ARRAY, []string{"echo in", "echo un"}},
+		{TAG_TRIGGERSCRIPTPROG, TYPE_STRING_ARRAY, []string{"/bin/sh", "/bin/bash"}},
+		{TAG_TRIGGERNAME, TYPE_STRING_ARRAY, []string{"httpd", "nginx", "httpd"}},
+		{TAG_TRIGGERVERSION, TYPE_STRING_ARRAY, []string{"2.4", "", ""}},
+		{TAG_TRIGGERFLAGS, TYPE_INT32, []int32{
+			SENSE_TRIGGERIN | SENSE_GREATER | SENSE_EQUAL, SENSE_TRIGGERIN, SENSE_TRIGGERUN,
+		}},
+		{TAG_TRIGGERINDEX, TYPE_INT32, []int32{0, 0, 1}},
+	}, false)
+
+	hdr, err := readHeader(bytes.NewReader(data), false)
+	c.Assert(err, IsNil)
+
+	c.Assert(extractHeaderScriptlets(hdr), DeepEquals, []*Scriptlet{
+		{Phase: PHASE_PRE, Interpreter: "/bin/sh", Body: "useradd -r app"},
+		{Phase: PHASE_POST, Interpreter: "<lua>", Body: "print(\"ok\")"},
+		{Phase: PHASE_POSTUN, Interpreter: "/bin/sh -e", Body: "systemctl daemon-reload"},
+		{Phase: PHASE_POSTTRANS, Interpreter: "/sbin/ldconfig"},
+		{Phase: PHASE_TRIGGERIN, Interpreter: "/bin/sh", Body: "echo in", Triggers: []string{"httpd >= 2.4", "nginx"}},
+		{Phase: PHASE_TRIGGERUN, Interpreter: "/bin/bash", Body: "echo un", Triggers: []string{"httpd"}},
+	})
+}
+
+func (s *RPMSuite) TestNativeReader(c *C) {
+	dir := c.MkDir()
+	file := dir + "/app-1.0-1.el9.x86_64.rpm"
+
+	var payload bytes.Buffer
+
+	gw := gzip.NewWriter(&payload)
+	gw.Write(genTestCPIO([]testCPIOEntry{{"./etc/app.conf", 1, 0100644, 1, "port 80\n"}}))
+	gw.Close()
+
+	err := os.WriteFile(file, genTestRPM([]testHeaderEntry{
+		{TAG_NAME, TYPE_STRING, "app"},
+		{TAG_VERSION, TYPE_STRING, "1.0"},
+		{TAG_RELEASE, TYPE_STRING, "1.el9"},
+		{TAG_EPOCH, TYPE_INT32, []int32{1}},
+		{TAG_ARCH, TYPE_STRING, "x86_64"},
+		{TAG_SOURCERPM, TYPE_STRING, "app-1.0-1.el9.src.rpm"},
+		{TAG_REQUIRENAME, TYPE_STRING_ARRAY, []string{"bash", "libc.so.6()(64bit)"}},
+		{TAG_REQUIREFLAGS, TYPE_INT32, []int32{SENSE_GREATER | SENSE_EQUAL, 0}},
+		{TAG_REQUIREVERSION, TYPE_STRING_ARRAY, []string{"4.0", ""}},
+		{TAG_BASENAMES, TYPE_STRING_ARRAY, []string{"app.conf"}},
+		{TAG_DIRNAMES, TYPE_STRING_ARRAY, []string{"/etc/"}},
+		{TAG_DIRINDEXES, TYPE_INT32, []int32{0}},
+		{TAG_FILEMODES, TYPE_INT16, []uint16{0100644}},
+		{TAG_FILEUSERNAME, TYPE_STRING_ARRAY, []string{"root"}},
+		{TAG_FILEGROUPNAME, TYPE_STRING_ARRAY, []string{"root"}},
+		{TAG_PREIN, TYPE_STRING, "useradd -r app"},
+	}, payload.Bytes()), 0644)
+
+	c.Assert(err, IsNil)
+	c.Assert(os.WriteFile(dir+"/app.txt", []byte("app"), 0644), IsNil)
+
+	SetReaders(&NativeReader{})
+
+	c.Assert(IsPackage(file), Equals, true)
+	c.Assert(IsPackage(dir+"/app.txt"), Equals, false)
+
+	pkg, err := ReadRPM(file)
+
+	c.Assert(err, IsNil)
+	c.Assert(pkg.NEVRA(), Equals, "app-1:1.0-1.el9.x86_64")
+	c.Assert(pkg.Dist, Equals, "el9")
+	c.Assert(pkg.SourceName(), Equals, "app")
+	c.Assert(pkg.IsSrc, Equals, false)
+	c.Assert(pkg.Deps.Requires, HasLen, 2)
+	c.Assert(pkg.Deps.Requires[0].String(), Equals, "bash >= 4.0")
+	c.Assert(pkg.Scriptlets, HasLen, 1)
+	c.Assert(pkg.Payload, HasLen, 1)
+	c.Assert(pkg.Payload[0].Path, Equals, "/etc/app.conf")
+
+	info, err := ReadInfo(file)
+
+	c.Assert(err, IsNil)
+	c.Assert(info.Name, Equals, "app")
+	c.Assert(info.Payload, IsNil)
+	c.Assert(info.Scriptlets, IsNil)
+
+	contents := make(map[string]string)
+
+	err = pkg.ReadFiles([]string{"/etc/app.conf"}, func(path string, data []byte) error {
+		contents[path] = string(data)
+		return nil
+	})
+
+	c.Assert(err, IsNil)
+	c.Assert(contents, DeepEquals, map[string]string{"/etc/app.conf": "port 80\n"})
+
+	_, err = ReadRPM(dir + "/app.txt")
+	c.Assert(err, NotNil)
+}
+
+func (s *RPMSuite) TestParsePayloadInfo(c *C) {
+	digest := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	noDigest := strings.Repeat("0", 64)
+
+	testCases := []struct {
+		Line   string
+		Object *Object
+	}{
+		{
+			"/usr/bin/app 1024 1700000000 " + digest + " 0100755 root root 0 0 0 X",
+			&Object{
+				Path: "/usr/bin/app", User: "root", Group: "root", Digest: digest,
+				Size: 1024, MTime: time.Unix(1700000000, 0), Mode: 0755,
+			},
+		},
+		{
+			"/usr/share/app/My Docs 17 1700000000 " + noDigest + " 0120777 root root 0 1 0 ../doc/app files",
+			&Object{
+				Path: "/usr/share/app/My Docs", User: "root", Group: "root", LinkTo: "../doc/app files",
+				Size: 17, MTime: time.Unix(1700000000, 0), Mode: 0777, IsDoc: true, IsLink: true,
+			},
+		},
+		{
+			"/var/lib/app 4096 1700000000 " + noDigest + " 040750 app app 0 0 0 X",
+			&Object{
+				Path: "/var/lib/app", User: "app", Group: "app",
+				Size: 4096, MTime: time.Unix(1700000000, 0), Mode: 0750, IsDir: true,
+			},
+		},
+		{
+			// Ghost config file
+			"/var/log/app/app.log 0 1700000000 " + noDigest + " 0100640 app adm 1 0 0 X",
+			&Object{
+				Path: "/var/log/app/app.log", User: "app", Group: "adm",
+				MTime: time.Unix(1700000000, 0), Mode: 0640, IsConfig: true,
+			},
+		},
+		{
+			"/dev/app 0 1700000000 " + noDigest + " 020660 root root 0 0 0x0103 X",
+			&Object{
+				Path: "/dev/app", User: "root", Group: "root",
+				MTime: time.Unix(1700000000, 0), Rdev: 0x0103, Mode: 0660,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		obj, err := parsePayloadInfo(tc.Line)
+
+		c.Assert(err, IsNil, Commentf("Line: %s", tc.Line))
+		c.Assert(obj, DeepEquals, tc.Object, Commentf("Line: %s", tc.Line))
+	}
+
+	_, err := parsePayloadInfo("/usr/bin/app 1024 1700000000")
+	c.Assert(err, NotNil)
+
+	payload, err := parseDumpData(testCases[0].Line + "\n" + testCases[2].Line + "\n")
+
+	c.Assert(err, IsNil)
+	c.Assert(payload, HasLen, 2)
+
+	_, err = parseDumpData(testCases[0].Line + "\nunknown\n")
+	c.Assert(err, NotNil)
+}
+
+func (s *RPMSuite) TestParseScriptlets(c *C) {
+	data := "pretrans scriptlet (using <lua>):\n" +
+		"print(\"pretrans\")\n" +
+		"preinstall scriptlet (using /bin/sh):\n" +
+		"getent group app >/dev/null || groupadd -r app\n" +
+		"\n" +
+		"getent passwd app >/dev/null || useradd -r -g app app\n" +
+		"\n" +
+		"postinstall program: /sbin/ldconfig\n" +
+		"preuninstall scriptlet (using /bin/sh -e):\n" +
+		"systemctl stop app.service\n" +
+		"postuninstall scriptlet (using /bin/bash):\n" +
+		"systemctl daemon-reload\n" +
+		"posttrans scriptlet (using /usr/bin/sh):\n" +
+		"true\n" +
+		"triggerin scriptlet (using /bin/sh) -- httpd >= 2.4, nginx\n" +
+		"systemctl reload app.service\n" +
+		"triggerun scriptlet (using /bin/sh) -- httpd:\n" +
+		"systemctl restart app.service\n"
+
+	c.Assert(parseScriptlets(data), DeepEquals, []*Scriptlet{
+		{Phase: PHASE_PRETRANS, Interpreter: "<lua>", Body: "print(\"pretrans\")"},
+		{
+			Phase: PHASE_PRE, Interpreter: "/bin/sh",
+			Body: "getent group app >/dev/null || groupadd -r app\n\ngetent passwd app >/dev/null || useradd -r -g app app",
+		},
+		{Phase: PHASE_POST, Interpreter: "/sbin/ldconfig"},
+		{Phase: PHASE_PREUN, Interpreter: "/bin/sh -e", Body: "systemctl stop app.service"},
+		{Phase: PHASE_POSTUN, Interpreter: "/bin/bash", Body: "systemctl daemon-reload"},
+		{Phase: PHASE_POSTTRANS, Interpreter: "/usr/bin/sh", Body: "true"},
+		{
+			Phase: PHASE_TRIGGERIN, Interpreter: "/bin/sh",
+			Body: "systemctl reload app.service", Triggers: []string{"httpd >= 2.4", "nginx"},
+		},
+		{
+			Phase: PHASE_TRIGGERUN, Interpreter: "/bin/sh",
+			Body: "systemctl restart app.service", Triggers: []string{"httpd"},
+		},
+	})
+
+	c.Assert(parseScriptlets(""), IsNil)
+	c.Assert(parseScriptlets("unknown scriptlet (using /bin/sh):\ntrue\n"), IsNil)
+}
+
+func (s *RPMSuite) TestScriptletKind(c *C) {
+	testCases := []struct {
+		Phase       string
+		Interpreter string
+		IsInstall   bool
+		IsShell     bool
+	}{
+		{PHASE_PRETRANS, "<lua>", true, false},
+		{PHASE_PRE, "/bin/sh", true, true},
+		{PHASE_POST, "/bin/sh -e", true, true},
+		{PHASE_POST, "/sbin/ldconfig", true, false},
+		{PHASE_POSTTRANS, "/usr/bin/bash", true, true},
+		{PHASE_PREUN, "/bin/sh", false, true},
+		{PHASE_POSTUN, "/usr/bin/sh", false, true},
+		{PHASE_PREUNTRANS, "/bin/bash", false, true},
+		{PHASE_POSTUNTRANS, "/bin/sh", false, true},
+		{PHASE_TRIGGERPREIN, "/bin/sh", true, true},
+		{PHASE_TRIGGERIN, "/usr/bin/python3", true, false},
+		{PHASE_TRIGGERUN, "/bin/sh", false, true},
+		{PHASE_TRIGGERPOSTUN, "/bin/sh", false, true},
+	}
+
+	for _, tc := range testCases {
+		scriptlet := &Scriptlet{Phase: tc.Phase, Interpreter: tc.Interpreter}
+
+		c.Assert(scriptlet.IsInstall(), Equals, tc.IsInstall, Commentf("Phase: %s", tc.Phase))
+		c.Assert(scriptlet.IsShell(), Equals, tc.IsShell, Commentf("Interpreter: %s", tc.Interpreter))
+	}
+}
+
+func (s *RPMSuite) TestCompareVersions(c *C) {
+	// Test vectors from rpm test suite (tests/rpmvercmp.at)
+	testCases := []struct {
+		A      string
+		B      string
+		Result int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "2.0", -1},
+		{"2.0", "1.0", 1},
+		{"2.0.1", "2.0.1", 0},
+		{"2.0", "2.0.1", -1},
+		{"2.0.1", "2.0", 1},
+		{"2.0.1a", "2.0.1a", 0},
+		{"2.0.1a", "2.0.1", 1},
+		{"2.0.1", "2.0.1a", -1},
+		{"5.5p1", "5.5p1", 0},
+		{"5.5p1", "5.5p2", -1},
+		{"5.5p2", "5.5p1", 1},
+		{"5.5p10", "5.5p10", 0},
+		{"5.5p1", "5.5p10", -1},
+		{"5.5p10", "5.5p1", 1},
+		{"10xyz", "10.1xyz", -1},
+		{"10.1xyz", "10xyz", 1},
+		{"xyz10", "xyz10", 0},
+		{"xyz10", "xyz10.1", -1},
+		{"xyz10.1", "xyz10", 1},
+		{"xyz.4", "xyz.4", 0},
+		{"xyz.4", "8", -1},
+		{"8", "xyz.4", 1},
+		{"xyz.4", "2", -1},
+		{"2", "xyz.4", 1},
+		{"5.5p2", "5.6p1", -1},
+		{"5.6p1", "5.5p2", 1},
+		{"5.6p1", "6.5p1", -1},
+		{"6.5p1", "5.6p1", 1},
+		{"6.0.rc1", "6.0", 1},
+		{"6.0", "6.0.rc1", -1},
+		{"10b2", "10a1", 1},
+		{"10a2", "10b2", -1},
+		{"1.0aa", "1.0aa", 0},
+		{"1.0a", "1.0aa", -1},
+		{"1.0aa", "1.0a", 1},
+		{"10.0001", "10.0001", 0},
+		{"10.0001", "10.1", 0},
+		{"10.1", "10.0001", 0},
+		{"10.0001", "10.0039", -1},
+		{"10.0039", "10.0001", 1},
+		{"4.999.9", "5.0", -1},
+		{"5.0", "4.999.9", 1},
+		{"20101121", "20101121", 0},
+		{"20101121", "20101122", -1},
+		{"20101122", "20101121", 1},
+		{"2_0", "2_0", 0},
+		{"2.0", "2_0", 0},
+		{"2_0", "2.0", 0},
+		{"a", "a", 0},
+		{"a+", "a+", 0},
+		{"a+", "a_", 0},
+		{"a_", "a+", 0},
+		{"+a", "+a", 0},
+		{"+a", "_a", 0},
+		{"_a", "+a", 0},
+		{"+_", "+_", 0},
+		{"_+", "+_", 0},
+		{"_+", "_+", 0},
+		{"+", "_", 0},
+		{"_", "+", 0},
+		{"1.0~rc1", "1.0~rc1", 0},
+		{"1.0~rc1", "1.0", -1},
+		{"1.0", "1.0~rc1", 1},
+		{"1.0~rc1", "1.0~rc2", -1},
+		{"1.0~rc2", "1.0~rc1", 1},
+		{"1.0~rc1~git123", "1.0~rc1~git123", 0},
+		{"1.0~rc1~git123", "1.0~rc1", -1},
+		{"1.0~rc1", "1.0~rc1~git123", 1},
+		{"1.0^", "1.0^", 0},
+		{"1.0^", "1.0", 1},
+		{"1.0", "1.0^", -1},
+		{"1.0^git1", "1.0^git1", 0},
+		{"1.0^git1", "1.0", 1},
+		{"1.0", "1.0^git1", -1},
+		{"1.0^git1", "1.0^git2", -1},
+		{"1.0^git2", "1.0^git1", 1},
+		{"1.0^git1", "1.01", -1},
+		{"1.01", "1.0^git1", 1},
+		{"1.0^20160101", "1.0^20160101", 0},
+		{"1.0^20160101", "1.0.1", -1},
+		{"1.0.1", "1.0^20160101", 1},
+		{"1.0^20160101^git1", "1.0^20160101^git1", 0},
+		{"1.0^20160102", "1.0^20160101^git1", 1},
+		{"1.0^20160101^git1", "1.0^20160102", -1},
+		{"1.0~rc1^git1", "1.0~rc1^git1", 0},
+		{"1.0~rc1^git1", "1.0~rc1", 1},
+		{"1.0~rc1", "1.0~rc1^git1", -1},
+		{"1.0^git1~pre", "1.0^git1~pre", 0},
+		{"1.0^git1", "1.0^git1~pre", 1},
+		{"1.0^git1~pre", "1.0^git1", -1},
+		{"1b.fc17", "1b.fc17", 0},
+		{"1b.fc17", "1.fc17", -1},
+		{"1.fc17", "1b.fc17", 1},
+		{"1g.fc17", "1g.fc17", 0},
+		{"1g.fc17", "1.fc17", 1},
+		{"1.fc17", "1g.fc17", -1},
+	}
+
+	for _, tc := range testCases {
+		c.Assert(CompareVersions(tc.A, tc.B), Equals, tc.Result, Commentf("%s <=> %s", tc.A, tc.B))
+	}
+}
+
+func (s *RPMSuite) TestCompareEVR(c *C) {
+	testCases := []struct {
+		A      string
+		B      string
+		Result int
+	}{
+		{"1.0-1", "1.0-1", 0},
+		{"1.0-1", "1.0-2", -1},
+		{"1.0-10.el9", "1.0-9.el9", 1},
+		{"1.0", "1.0-5", 0},
+		{"1.0-5", "1.0", 0},
+		{"0:1.0-1", "1.0-1", 0},
+		{"1:1.0-1", "2.0-1", 1},
+		{"2.0-1", "1:1.0-1", -1},
+		{"1:1.0", "2:0.1", -1},
+		{"1.0~rc1-1", "1.0-1", -1},
+		{"1.0^git1-1", "1.0-2", 1},
+	}
+
+	for _, tc := range testCases {
+		c.Assert(CompareEVR(tc.A, tc.B), Equals, tc.Result, Commentf("%s <=> %s", tc.A, tc.B))
+	}
+}
+
+func (s *RPMSuite) TestDependencyMatches(c *C) {
+	testCases := []struct {
+		Require string
+		Provide string
+		Result  bool
+	}{
+		{"foo", "foo = 1.0-1", true},
+		{"foo >= 1.0", "foo", true},
+		{"foo", "bar = 1.0", false},
+		{"foo >= 1.0", "foo = 1.0-1", true},
+		{"foo >= 1.1", "foo = 1.0-1", false},
+		{"foo > 1.0", "foo = 1.0", false},
+		{"foo > 1.0", "foo = 1.0.1", true},
+		{"foo < 2.0", "foo = 1.5", true},
+		{"foo < 2.0", "foo = 2.0", false},
+		{"foo <= 2.0", "foo = 2.0", true},
+		{"foo = 1.0", "foo = 1.0-5", true},
+		{"foo = 1.0-4", "foo = 1.0-5", false},
+		{"foo = 1:1.0", "foo = 1.0", false},
+		{"foo >= 1.0", "foo = 1:0.5", true},
+		{"foo >= 1.0", "foo = 1.0~rc1", false},
+		{"foo >= 3.0", "foo >= 2.0", true},
+		{"foo <= 1.0", "foo >= 2.0", false},
+		{"foo < 2.0", "foo > 1.0", true},
+		{"foo > 2.0", "foo < 1.0", false},
+		{"foo = 2.0", "foo <= 2.0", true},
+		{"foo > 2.0", "foo <= 2.0", false},
+	}
+
+	for _, tc := range testCases {
+		c.Assert(
+			parseTestDep(tc.Require).Matches(parseTestDep(tc.Provide)), Equals, tc.Result,
+			Commentf("%s → %s", tc.Require, tc.Provide),
+		)
+	}
+
+	c.Assert((*Dependency)(nil).Matches(parseTestDep("foo")), Equals, false)
+	c.Assert(parseTestDep("foo").Matches(nil), Equals, false)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// testCPIOEntry contains info about entry of test cpio archive
+type testCPIOEntry struct {
+	Path  string
+	Inode int
+	Mode  int
+	Links int
+	Data  string
+}
+
+// genTestCPIO generates cpio archive in newc format with given entries
+func genTestCPIO(entries []testCPIOEntry) []byte {
+	var buf bytes.Buffer
+
+	write := func(path string, inode, mode, links int, data string) {
+		fmt.Fprintf(
+			&buf, "%s%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%s\x00",
+			cpioMagicNewc, inode, mode, 0, 0, links, 0, len(data), 0, 0, 0, 0, len(path)+1, 0, path,
+		)
+
+		buf.Write(make([]byte, cpioPadding(int64(CPIO_HEADER_SIZE+len(path)+1))))
+		buf.WriteString(data)
+		buf.Write(make([]byte, cpioPadding(int64(len(data)))))
+	}
+
+	for _, e := range entries {
+		write(e.Path, e.Inode, e.Mode, e.Links, e.Data)
+	}
+
+	write(cpioTrailer, 0, 0, 1, "")
+
+	return buf.Bytes()
+}
+
+// testHeaderEntry contains info about entry of test header
+type testHeaderEntry struct {
+	Tag   int32
+	Type  int32
+	Value any
+}
+
+// genTestHeader generates header with given entries. If align is true, store
+// is padded to 8-byte boundary like in signature header.
+func genTestHeader(entries []testHeaderEntry, align bool) []byte {
+	var index, store bytes.Buffer
+
+	for _, e := range entries {
+		var count int
+
+		offset := store.Len()
+
+		switch v := e.Value.(type) {
+		case string:
+			count = 1
+			store.WriteString(v + "\x00")
+		case []string:
+			count = len(v)
+			store.WriteString(strings.Join(v, "\x00") + "\x00")
+		case []uint16:
+			count = len(v)
+			binary.Write(&store, binary.BigEndian, v)
+		case []int32:
+			count = len(v)
+			binary.Write(&store, binary.BigEndian, v)
+		case []int64:
+			count = len(v)
+			binary.Write(&store, binary.BigEndian, v)
+		}
+
+		binary.Write(&index, binary.BigEndian, []int32{e.Tag, e.Type, int32(offset), int32(count)})
+	}
+
+	data := genTestHeaderIntro(len(entries), store.Len())
+	data = append(data, index.Bytes()...)
+	data = append(data, store.Bytes()...)
+
+	if align && store.Len()%8 != 0 {
+		data = append(data, make([]byte, 8-store.Len()%8)...)
+	}
+
+	return data
+}
+
+// genTestHeaderIntro generates header intro with given number of entries and
+// size of store
+func genTestHeaderIntro(count, size int) []byte {
+	intro := append([]byte{}, headerMagic...)
+	intro = binary.BigEndian.AppendUint32(intro, 0)
+	intro = binary.BigEndian.AppendUint32(intro, uint32(count))
+
+	return binary.BigEndian.AppendUint32(intro, uint32(size))
+}
+
+// genTestRPM generates binary package with given header entries and payload
+func genTestRPM(entries []testHeaderEntry, payload []byte) []byte {
+	lead := make([]byte, LEAD_SIZE)
+	copy(lead, leadMagic)
+	lead[4], lead[5] = 3, 0
+
+	data := append(lead, genTestHeader([]testHeaderEntry{{1000, TYPE_INT32, []int32{0}}}, true)...)
+	data = append(data, genTestHeader(entries, false)...)
+
+	return append(data, payload...)
+}
+
+// parseTestDep parses dependency in "name op version" format
+func parseTestDep(data string) *Dependency {
+	name, cond, _ := strings.Cut(data, " ")
+
+	if cond == "" {
+		return &Dependency{Name: name}
+	}
+
+	op, version, _ := strings.Cut(cond, " ")
+	dep := &Dependency{Name: name, Version: version}
+
+	if strings.Contains(op, "<") {
+		dep.Flags |= SENSE_LESS
+	}
+
+	if strings.Contains(op, ">") {
+		dep.Flags |= SENSE_GREATER
+	}
+
+	if strings.Contains(op, "=") {
+		dep.Flags |= SENSE_EQUAL
+	}
+
+	return dep
+}
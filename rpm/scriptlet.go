@@ -0,0 +1,161 @@
+package rpm
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bufio"
+	"regexp"
+	"strings"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Scriptlet phases
+const (
+	PHASE_PRETRANS      = "pretrans"
+	PHASE_PRE           = "pre"
+	PHASE_POST          = "post"
+	PHASE_PREUN         = "preun"
+	PHASE_POSTUN        = "postun"
+	PHASE_POSTTRANS     = "posttrans"
+	PHASE_PREUNTRANS    = "preuntrans"
+	PHASE_POSTUNTRANS   = "postuntrans"
+	PHASE_TRIGGERPREIN  = "triggerprein"
+	PHASE_TRIGGERIN     = "triggerin"
+	PHASE_TRIGGERUN     = "triggerun"
+	PHASE_TRIGGERPOSTUN = "triggerpostun"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Scriptlet contains info about package scriptlet
+type Scriptlet struct {
+	Phase       string
+	Interpreter string
+	Body        string
+	Triggers    []string // Trigger conditions (e.g. "httpd >= 2.4")
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// scriptletPhases is map rpm scriptlet name → phase
+var scriptletPhases = map[string]string{
+	"pretrans":      PHASE_PRETRANS,
+	"preinstall":    PHASE_PRE,
+	"postinstall":   PHASE_POST,
+	"preuninstall":  PHASE_PREUN,
+	"postuninstall": PHASE_POSTUN,
+	"posttrans":     PHASE_POSTTRANS,
+	"preuntrans":    PHASE_PREUNTRANS,
+	"postuntrans":   PHASE_POSTUNTRANS,
+	"triggerprein":  PHASE_TRIGGERPREIN,
+	"triggerin":     PHASE_TRIGGERIN,
+	"triggerun":     PHASE_TRIGGERUN,
+	"triggerpostun": PHASE_TRIGGERPOSTUN,
+}
+
+var (
+	scriptletHeaderRegex = regexp.MustCompile(`^([a-z]+) scriptlet \(using ([^)]*)\)(?: -- (.*?))?:?$`)
+	scriptletProgRegex   = regexp.MustCompile(`^([a-z]+) program: (.+)$`)
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// IsInstall returns true if scriptlet is executed on package install or upgrade
+func (s *Scriptlet) IsInstall() bool {
+	switch s.Phase {
+	case PHASE_PRETRANS, PHASE_PRE, PHASE_POST, PHASE_POSTTRANS,
+		PHASE_TRIGGERPREIN, PHASE_TRIGGERIN:
+		return true
+	}
+
+	return false
+}
+
+// IsShell returns true if scriptlet is executed by shell
+func (s *Scriptlet) IsShell() bool {
+	interpreter, _, _ := strings.Cut(s.Interpreter, " ")
+
+	switch interpreter {
+	case "/bin/sh", "/bin/bash", "/usr/bin/sh", "/usr/bin/bash":
+		return true
+	}
+
+	return false
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// parseScriptlets parses output of rpm --scripts and rpm --triggers commands
+func parseScriptlets(data string) []*Scriptlet {
+	var result []*Scriptlet
+	var current *Scriptlet
+	var body []string
+
+	flush := func() {
+		if current != nil {
+			current.Body = strings.TrimRight(strings.Join(body, "\n"), "\n")
+			result = append(result, current)
+		}
+
+		current, body = nil, nil
+	}
+
+	s := bufio.NewScanner(strings.NewReader(data))
+	s.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for s.Scan() {
+		line := s.Text()
+
+		if m := scriptletHeaderRegex.FindStringSubmatch(line); m != nil && scriptletPhases[m[1]] != "" {
+			flush()
+			current = &Scriptlet{
+				Phase:       scriptletPhases[m[1]],
+				Interpreter: m[2],
+				Triggers:    parseTriggerConditions(m[3]),
+			}
+			continue
+		}
+
+		if m := scriptletProgRegex.FindStringSubmatch(line); m != nil && scriptletPhases[m[1]] != "" {
+			flush()
+			result = append(result, &Scriptlet{
+				Phase:       scriptletPhases[m[1]],
+				Interpreter: m[2],
+			})
+			continue
+		}
+
+		if current != nil {
+			body = append(body, line)
+		}
+	}
+
+	flush()
+
+	return result
+}
+
+// parseTriggerConditions parses comma-separated list of trigger conditions
+func parseTriggerConditions(data string) []string {
+	if data == "" {
+		return nil
+	}
+
+	var result []string
+
+	for _, cond := range strings.Split(data, ",") {
+		cond = strings.TrimSpace(cond)
+
+		if cond != "" {
+			result = append(result, cond)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,182 @@
+package rpm
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"strings"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// CompareEVR compares two EVR strings ([epoch:]version[-release]) using rpm rules.
+// Release is compared only if it is present in both strings.
+func CompareEVR(a, b string) int {
+	ae, av, ar := splitEVR(a)
+	be, bv, br := splitEVR(b)
+
+	if ae == "" {
+		ae = "0"
+	}
+
+	if be == "" {
+		be = "0"
+	}
+
+	if r := CompareVersions(ae, be); r != 0 {
+		return r
+	}
+
+	if r := CompareVersions(av, bv); r != 0 {
+		return r
+	}
+
+	if ar == "" || br == "" {
+		return 0
+	}
+
+	return CompareVersions(ar, br)
+}
+
+// CompareVersions compares two version or release strings using rpmvercmp
+// algorithm
+func CompareVersions(a, b string) int {
+	if a == b {
+		return 0
+	}
+
+	for len(a) > 0 || len(b) > 0 {
+		a = strings.TrimLeftFunc(a, isVersionSeparator)
+		b = strings.TrimLeftFunc(b, isVersionSeparator)
+
+		// Tilde sorts before everything, even the end of string
+		if strings.HasPrefix(a, "~") || strings.HasPrefix(b, "~") {
+			if !strings.HasPrefix(a, "~") {
+				return 1
+			}
+
+			if !strings.HasPrefix(b, "~") {
+				return -1
+			}
+
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		// Caret sorts after the end of string, but before anything else
+		if strings.HasPrefix(a, "^") || strings.HasPrefix(b, "^") {
+			switch {
+			case a == "":
+				return -1
+			case b == "":
+				return 1
+			case !strings.HasPrefix(a, "^"):
+				return 1
+			case !strings.HasPrefix(b, "^"):
+				return -1
+			}
+
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		if a == "" || b == "" {
+			break
+		}
+
+		isNum := isDigit(a[0])
+
+		var as, bs string
+
+		if isNum {
+			as, a = splitSegment(a, isDigit)
+			bs, b = splitSegment(b, isDigit)
+		} else {
+			as, a = splitSegment(a, isAlpha)
+			bs, b = splitSegment(b, isAlpha)
+		}
+
+		// Segments of different types: numeric segment is always newer
+		if bs == "" {
+			if isNum {
+				return 1
+			}
+
+			return -1
+		}
+
+		if isNum {
+			as = strings.TrimLeft(as, "0")
+			bs = strings.TrimLeft(bs, "0")
+
+			if len(as) != len(bs) {
+				if len(as) > len(bs) {
+					return 1
+				}
+
+				return -1
+			}
+		}
+
+		if r := strings.Compare(as, bs); r != 0 {
+			return r
+		}
+	}
+
+	switch {
+	case a == "" && b == "":
+		return 0
+	case a == "":
+		return -1
+	}
+
+	return 1
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// splitEVR splits EVR string to epoch, version and release
+func splitEVR(evr string) (string, string, string) {
+	var epoch, release string
+
+	if i := strings.IndexRune(evr, ':'); i != -1 {
+		epoch, evr = evr[:i], evr[i+1:]
+	}
+
+	if i := strings.LastIndex(evr, "-"); i != -1 {
+		evr, release = evr[:i], evr[i+1:]
+	}
+
+	return epoch, evr, release
+}
+
+// splitSegment splits string to leading segment with symbols which match given
+// function and the rest of the string
+func splitSegment(s string, fn func(byte) bool) (string, string) {
+	i := 0
+
+	for i < len(s) && fn(s[i]) {
+		i++
+	}
+
+	return s[:i], s[i:]
+}
+
+// isVersionSeparator returns true if given rune is not a part of version segment
+func isVersionSeparator(r rune) bool {
+	return r != '~' && r != '^' && !(r < 128 && (isDigit(byte(r)) || isAlpha(byte(r))))
+}
+
+// isDigit returns true if given symbol is a digit
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// isAlpha returns true if given symbol is ASCII letter
+func isAlpha(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,404 @@
+package systemd
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bufio"
+	"bytes"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Service types
+const (
+	TYPE_SIMPLE  = "simple"
+	TYPE_EXEC    = "exec"
+	TYPE_FORKING = "forking"
+	TYPE_ONESHOT = "oneshot"
+	TYPE_DBUS    = "dbus"
+	TYPE_NOTIFY  = "notify"
+	TYPE_IDLE    = "idle"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Unit kinds
+const (
+	KIND_SERVICE = "service"
+	KIND_SOCKET  = "socket"
+	KIND_TIMER   = "timer"
+	KIND_PATH    = "path"
+	KIND_TARGET  = "target"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Unit contains info from unit file
+type Unit struct {
+	Name            string
+	DefaultInstance string
+	Sections        map[string]Section
+	Service         *Service
+	Socket          *Socket
+	Timer           *Timer
+	Path            *Path
+}
+
+// Section contains unit section properties
+type Section map[string][]string
+
+// Service contains info from [Service] section
+type Service struct {
+	Type             string
+	User             string
+	Group            string
+	PIDFile          string
+	ExecStart        []string
+	EnvironmentFiles []*EnvironmentFile
+	TimeoutStart     time.Duration
+}
+
+// Socket contains info from [Socket] section
+type Socket struct {
+	ListenStream   []string
+	ListenDatagram []string
+	Accept         bool
+	Service        string
+}
+
+// Timer contains info from [Timer] section
+type Timer struct {
+	OnCalendar []string
+	Persistent bool
+	Unit       string
+}
+
+// Path contains info from [Path] section
+type Path struct {
+	Watch []string
+	Unit  string
+}
+
+// EnvironmentFile contains info about environment file
+type EnvironmentFile struct {
+	Path       string
+	IsOptional bool
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// timeUnits contains systemd time span units
+var timeUnits = map[string]time.Duration{
+	"us": time.Microsecond, "usec": time.Microsecond,
+	"ms": time.Millisecond, "msec": time.Millisecond,
+	"": time.Second, "s": time.Second, "sec": time.Second, "second": time.Second, "seconds": time.Second,
+	"m": time.Minute, "min": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hour": time.Hour, "hours": time.Hour,
+	"d": 24 * time.Hour, "day": 24 * time.Hour, "days": 24 * time.Hour,
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Parse parses unit file data
+func Parse(name string, data []byte) *Unit {
+	unit := &Unit{
+		Name:     name,
+		Sections: make(map[string]Section),
+	}
+
+	var section, line string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		text := strings.TrimSpace(scanner.Text())
+
+		if line == "" && (text == "" || text[0] == '#' || text[0] == ';') {
+			continue
+		}
+
+		if strings.HasSuffix(text, "\\") {
+			line += strings.TrimSuffix(text, "\\") + " "
+			continue
+		}
+
+		line += text
+
+		switch {
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			section = strings.Trim(line, "[]")
+
+			if unit.Sections[section] == nil {
+				unit.Sections[section] = make(Section)
+			}
+
+		case section != "" && strings.Contains(line, "="):
+			key, value, _ := strings.Cut(line, "=")
+			key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+			if value == "" {
+				// Empty value resets list of values
+				delete(unit.Sections[section], key)
+			} else {
+				unit.Sections[section][key] = append(unit.Sections[section][key], value)
+			}
+		}
+
+		line = ""
+	}
+
+	unit.DefaultInstance = unit.Get("Install", "DefaultInstance")
+
+	switch unit.Kind() {
+	case KIND_SERVICE:
+		unit.Service = parseService(unit)
+	case KIND_SOCKET:
+		unit.Socket = parseSocket(unit)
+	case KIND_TIMER:
+		unit.Timer = parseTimer(unit)
+	case KIND_PATH:
+		unit.Path = parsePath(unit)
+	}
+
+	return unit
+}
+
+// TemplateName returns name of template unit for given unit instance
+// (e.g. foo@main.service → foo@.service)
+func TemplateName(name string) string {
+	prefix, _, ok := strings.Cut(name, "@")
+
+	if !ok {
+		return name
+	}
+
+	return prefix + "@" + path.Ext(name)
+}
+
+// Expand expands specifiers (%i, %I, %p, %n…) in given value for unit with
+// given name
+func Expand(value, name string) string {
+	if !strings.Contains(value, "%") {
+		return value
+	}
+
+	base := strings.TrimSuffix(name, path.Ext(name))
+	prefix, instance, _ := strings.Cut(base, "@")
+
+	return strings.NewReplacer(
+		"%%", "%",
+		"%i", instance, "%I", instance,
+		"%p", prefix, "%P", prefix,
+		"%n", name, "%N", base,
+	).Replace(value)
+}
+
+// IsBool returns true if given unit file value is a positive boolean
+func IsBool(value string) bool {
+	switch strings.ToLower(value) {
+	case "1", "yes", "true", "on":
+		return true
+	}
+
+	return false
+}
+
+// ParseTimeSpan parses systemd time span (e.g. "90", "1min 30s")
+func ParseTimeSpan(value string) time.Duration {
+	var result time.Duration
+
+	value = strings.TrimSpace(value)
+
+	if value == "" || value == "infinity" {
+		return 0
+	}
+
+	for _, field := range strings.Fields(value) {
+		index := strings.IndexFunc(field, func(r rune) bool {
+			return (r < '0' || r > '9') && r != '.'
+		})
+
+		num, unit := field, ""
+
+		if index != -1 {
+			num, unit = field[:index], field[index:]
+		}
+
+		n, err := strconv.ParseFloat(num, 64)
+		mult, ok := timeUnits[unit]
+
+		if err != nil || !ok {
+			return 0
+		}
+
+		result += time.Duration(n * float64(mult))
+	}
+
+	return result
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Kind returns unit kind (service, socket, timer…)
+func (u *Unit) Kind() string {
+	return strings.TrimPrefix(path.Ext(u.Name), ".")
+}
+
+// BaseName returns unit name without kind suffix
+func (u *Unit) BaseName() string {
+	return strings.TrimSuffix(u.Name, path.Ext(u.Name))
+}
+
+// IsTemplate returns true if unit is template (e.g. foo@.service)
+func (u *Unit) IsTemplate() bool {
+	return strings.HasSuffix(u.BaseName(), "@")
+}
+
+// Get returns the last value of property from given section
+func (u *Unit) Get(section, key string) string {
+	values := u.GetAll(section, key)
+
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[len(values)-1]
+}
+
+// GetAll returns all values of property from given section
+func (u *Unit) GetAll(section, key string) []string {
+	if u == nil || u.Sections[section] == nil {
+		return nil
+	}
+
+	return u.Sections[section][key]
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// IsNotifying returns true if systemctl waits until service is ready
+func (s *Service) IsNotifying() bool {
+	switch s.Type {
+	case TYPE_FORKING, TYPE_ONESHOT, TYPE_DBUS, TYPE_NOTIFY:
+		return true
+	}
+
+	return false
+}
+
+// Instantiate returns copy of service info with expanded specifiers for
+// instance with given name
+func (s *Service) Instantiate(name string) *Service {
+	result := *s
+
+	result.User = Expand(s.User, name)
+	result.Group = Expand(s.Group, name)
+	result.PIDFile = Expand(s.PIDFile, name)
+	result.EnvironmentFiles = nil
+
+	for _, file := range s.EnvironmentFiles {
+		result.EnvironmentFiles = append(result.EnvironmentFiles, &EnvironmentFile{
+			Path:       Expand(file.Path, name),
+			IsOptional: file.IsOptional,
+		})
+	}
+
+	return &result
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// parseService parses info from [Service] section
+func parseService(unit *Unit) *Service {
+	service := &Service{
+		Type:         unit.Get("Service", "Type"),
+		User:         unit.Get("Service", "User"),
+		Group:        unit.Get("Service", "Group"),
+		PIDFile:      unit.Get("Service", "PIDFile"),
+		ExecStart:    unit.GetAll("Service", "ExecStart"),
+		TimeoutStart: ParseTimeSpan(unit.Get("Service", "TimeoutStartSec")),
+	}
+
+	if service.TimeoutStart == 0 {
+		service.TimeoutStart = ParseTimeSpan(unit.Get("Service", "TimeoutSec"))
+	}
+
+	if service.Type == "" {
+		switch {
+		case unit.Get("Service", "BusName") != "":
+			service.Type = TYPE_DBUS
+		case len(service.ExecStart) == 0:
+			service.Type = TYPE_ONESHOT
+		default:
+			service.Type = TYPE_SIMPLE
+		}
+	}
+
+	for _, file := range unit.GetAll("Service", "EnvironmentFile") {
+		service.EnvironmentFiles = append(service.EnvironmentFiles, &EnvironmentFile{
+			Path:       strings.TrimPrefix(file, "-"),
+			IsOptional: strings.HasPrefix(file, "-"),
+		})
+	}
+
+	return service
+}
+
+// parseSocket parses info from [Socket] section
+func parseSocket(unit *Unit) *Socket {
+	socket := &Socket{
+		ListenStream:   unit.GetAll("Socket", "ListenStream"),
+		ListenDatagram: unit.GetAll("Socket", "ListenDatagram"),
+		Accept:         IsBool(unit.Get("Socket", "Accept")),
+		Service:        unit.Get("Socket", "Service"),
+	}
+
+	if socket.Service == "" {
+		if socket.Accept {
+			socket.Service = unit.BaseName() + "@.service"
+		} else {
+			socket.Service = unit.BaseName() + ".service"
+		}
+	}
+
+	return socket
+}
+
+// parseTimer parses info from [Timer] section
+func parseTimer(unit *Unit) *Timer {
+	timer := &Timer{
+		OnCalendar: unit.GetAll("Timer", "OnCalendar"),
+		Persistent: IsBool(unit.Get("Timer", "Persistent")),
+		Unit:       unit.Get("Timer", "Unit"),
+	}
+
+	if timer.Unit == "" {
+		timer.Unit = unit.BaseName() + ".service"
+	}
+
+	return timer
+}
+
+// parsePath parses info from [Path] section
+func parsePath(unit *Unit) *Path {
+	p := &Path{Unit: unit.Get("Path", "Unit")}
+
+	for _, key := range []string{"PathExists", "PathExistsGlob", "PathChanged", "PathModified", "DirectoryNotEmpty"} {
+		p.Watch = append(p.Watch, unit.GetAll("Path", key)...)
+	}
+
+	if p.Unit == "" {
+		p.Unit = unit.BaseName() + ".service"
+	}
+
+	return p
+}